@@ -7,6 +7,8 @@ import (
 	"github.com/spf13/cobra"
 )
 
+const Version = "0.1.0"
+
 var RootCmd = &cobra.Command{
 	Use:   "nostalgic-rewind",
 	Short: "Nostalgic Rewind streams classic arcade games to Facebook Live",
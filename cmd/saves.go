@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zachlatta/nostalgic-rewind/game"
+	"github.com/zachlatta/nostalgic-rewind/rom"
+)
+
+var savesRomPath string
+var forceImport bool
+
+var savesCmd = &cobra.Command{
+	Use:   "saves",
+	Short: "Manage a ROM's saves",
+}
+
+var listSavesCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the snapshots kept of a ROM's saves",
+	Run: func(cmd *cobra.Command, args []string) {
+		romSaveDir := requireRomSaveDir()
+
+		snapshots, err := game.ListSnapshots(romSaveDir)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error listing snapshots:", err)
+			os.Exit(1)
+		}
+
+		if len(snapshots) == 0 {
+			fmt.Println("No snapshots in", romSaveDir)
+			return
+		}
+
+		for _, snapshot := range snapshots {
+			fmt.Printf("%s  (%s)\n", snapshot.Time.Format(game.SnapshotTimeLayout), snapshot.Time.Local().Format(time.RFC1123))
+		}
+	},
+}
+
+var restoreSavesCmd = &cobra.Command{
+	Use:   "restore [timestamp]",
+	Short: "Replace a ROM's current saves with a snapshot (stop the stream first)",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, "Please provide the timestamp of the snapshot to restore. See `saves list`.")
+			os.Exit(1)
+		}
+
+		romSaveDir := requireRomSaveDir()
+
+		at, err := time.Parse(game.SnapshotTimeLayout, args[0])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Invalid timestamp:", err)
+			os.Exit(1)
+		}
+
+		if err := game.RestoreSnapshot(romSaveDir, at); err != nil {
+			fmt.Fprintln(os.Stderr, "Error restoring snapshot:", err)
+			os.Exit(1)
+		}
+
+		fmt.Println("Restored saves from", args[0])
+		fmt.Println("The saves that were replaced are kept as the previous generation.")
+	},
+}
+
+var exportSavesCmd = &cobra.Command{
+	Use:   "export [archive path]",
+	Short: "Bundle a ROM's saves and their metadata into an archive",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, "Please provide a path to write the archive to.")
+			os.Exit(1)
+		}
+
+		romSaveDir := requireRomSaveDir()
+
+		f, err := os.Create(args[0])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error creating archive:", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+
+		metadata, err := game.ExportArchive(f, romSaveDir, savesRomPath, Version)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error exporting saves:", err)
+			os.Remove(args[0])
+			os.Exit(1)
+		}
+
+		fmt.Printf("Exported %d files to %s\n", len(metadata.Files), args[0])
+	},
+}
+
+var importSavesCmd = &cobra.Command{
+	Use:   "import [archive path]",
+	Short: "Replace a ROM's saves with the ones in an archive (stop the stream first)",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, "Please provide a path to the archive to import.")
+			os.Exit(1)
+		}
+
+		romSaveDir := requireRomSaveDir()
+
+		f, err := os.Open(args[0])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error opening archive:", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+
+		metadata, err := game.ImportArchive(f, romSaveDir, forceImport)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error importing saves:", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Imported %d files saved at %s by version %s\n", len(metadata.Files), metadata.SavedAt.Local().Format(time.RFC1123), metadata.AppVersion)
+		fmt.Println("The saves that were replaced are kept as the previous generation.")
+	},
+}
+
+var importSRAMCmd = &cobra.Command{
+	Use:   "import-sav [.sav path]",
+	Short: "Start a ROM's run from a battery save (.sav) made by another emulator (stop the stream first)",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, "Please provide a path to the .sav to import.")
+			os.Exit(1)
+		}
+
+		romSaveDir := requireRomSaveDir()
+
+		sram, err := ioutil.ReadFile(args[0])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error reading .sav:", err)
+			os.Exit(1)
+		}
+
+		err = game.ImportSRAM(romSaveDir, sram, forceImport)
+		if err == game.ErrSavestateExists {
+			fmt.Fprintln(os.Stderr, "Error importing .sav:", err)
+			fmt.Fprintln(os.Stderr, "Use --force to replace it. It'll be kept as a snapshot.")
+			os.Exit(1)
+		} else if err != nil {
+			fmt.Fprintln(os.Stderr, "Error importing .sav:", err)
+			os.Exit(1)
+		}
+
+		fmt.Println("Imported", args[0])
+		fmt.Println("The game will boot from it the next time the stream starts.")
+	},
+}
+
+func requireRomSaveDir() string {
+	if savesRomPath == "" {
+		fmt.Fprintln(os.Stderr, "Path to the ROM is required.")
+		os.Exit(1)
+	}
+
+	romPath := savesRomPath
+	if len(patchPaths) > 0 {
+		romPath = patchRom(romPath)
+	}
+
+	romSaveDir, err := game.RomSaveDir(savePath, romPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error finding saves for ROM:", err)
+		os.Exit(1)
+	}
+
+	return romSaveDir
+}
+
+// Like requireRomSaveDir, but without writing the patched ROM or moving saves,
+// for commands that only read them.
+func findRomSaveDir() string {
+	if savesRomPath == "" {
+		fmt.Fprintln(os.Stderr, "Path to the ROM is required.")
+		os.Exit(1)
+	}
+
+	// Saves were only ever keyed by ROM path before patches were supported, so
+	// a patched ROM's are always keyed by its hash.
+	if len(patchPaths) > 0 {
+		patched, err := rom.ReadPatched(savesRomPath, patchPaths)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error patching ROM:", err)
+			os.Exit(1)
+		}
+
+		return game.RomHashSaveDir(savePath, rom.Hash(patched))
+	}
+
+	romSaveDir, err := game.FindRomSaveDir(savePath, savesRomPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error finding saves for ROM:", err)
+		os.Exit(1)
+	}
+
+	return romSaveDir
+}
+
+func init() {
+	RootCmd.AddCommand(savesCmd)
+	savesCmd.PersistentFlags().StringVarP(&savesRomPath, "rom", "r", "", "Path to the ROM whose saves to manage")
+	savesCmd.PersistentFlags().StringVarP(&savePath, "save", "s", "./.saves", "The directory the emulator's state is saved in")
+	savesCmd.PersistentFlags().StringSliceVar(&patchPaths, "patch", nil, "Patches the ROM was played with, in the order they were applied")
+
+	savesCmd.AddCommand(listSavesCmd)
+	savesCmd.AddCommand(restoreSavesCmd)
+
+	savesCmd.AddCommand(exportSavesCmd)
+
+	savesCmd.AddCommand(importSavesCmd)
+	importSavesCmd.Flags().BoolVarP(&forceImport, "force", "f", false, "Import the archive even if it's for a different ROM")
+
+	savesCmd.AddCommand(importSRAMCmd)
+	importSRAMCmd.Flags().BoolVarP(&forceImport, "force", "f", false, "Replace the current savestate, keeping it as a snapshot")
+}
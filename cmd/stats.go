@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zachlatta/nostalgic-rewind/game"
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show the statistics of a ROM's run",
+	Run: func(cmd *cobra.Command, args []string) {
+		romSaveDir := findRomSaveDir()
+
+		save, err := game.LoadSave(filepath.Join(romSaveDir, game.GameSavePath))
+		if os.IsNotExist(err) {
+			fmt.Fprintln(os.Stderr, "No save in", romSaveDir)
+			os.Exit(1)
+		} else if err != nil {
+			fmt.Fprintln(os.Stderr, "Error loading save:", err)
+			os.Exit(1)
+		}
+
+		stats := save.Stats
+
+		fmt.Println("Uptime:", save.Uptime.Truncate(time.Second))
+		fmt.Println("Play time:", save.PlayTime.Truncate(time.Second))
+		fmt.Println("Button presses:", save.ButtonPresses)
+		fmt.Println("Party wipes:", stats.Wipes)
+		fmt.Println("Battles:", stats.Battles)
+		fmt.Println("Battles fled:", stats.BattlesFled)
+		fmt.Println("Steps:", stats.Steps)
+		fmt.Println("Inputs:", stats.InputSummary())
+
+		if len(save.Milestones) > 0 {
+			fmt.Println()
+			fmt.Println("Milestones:")
+
+			for _, milestone := range save.Milestones {
+				fmt.Printf("  %s  %s\n", milestone.At.Local().Format(time.RFC1123), milestone.Name)
+			}
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(statsCmd)
+	statsCmd.Flags().StringVarP(&savesRomPath, "rom", "r", "", "Path to the ROM whose run to show")
+	statsCmd.Flags().StringVarP(&savePath, "save", "s", "./.saves", "The directory the emulator's state is saved in")
+	statsCmd.Flags().StringSliceVar(&patchPaths, "patch", nil, "Patches the ROM was played with, in the order they were applied")
+}
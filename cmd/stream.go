@@ -1,15 +1,17 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
 
 	"github.com/spf13/cobra"
+	"github.com/zachlatta/nostalgic-rewind/emulator"
 	"github.com/zachlatta/nostalgic-rewind/facebook"
 	"github.com/zachlatta/nostalgic-rewind/game"
-	"github.com/zachlatta/nostalgic-rewind/util"
+	"github.com/zachlatta/nostalgic-rewind/rom"
+	"github.com/zachlatta/nostalgic-rewind/votes"
 )
 
 var accessToken string
@@ -17,6 +19,22 @@ var romPath string
 var vidId string
 var vidStreamUrl string
 var savePath string
+var voteAddr string
+var voteSecret string
+var metricsAddr string
+var webhookAddr string
+var webhookAppSecret string
+var webhookVerifyToken string
+var saveRetention string
+var romDbPath string
+var patchPaths []string
+var cheatsPath string
+var memoryMapPath string
+var milestonesPath string
+var postMilestones bool
+var controlsPath string
+var macrosPath string
+var nameEntryPath string
 
 var streamCmd = &cobra.Command{
 	Use:   "stream",
@@ -67,34 +85,75 @@ var playStreamCmd = &cobra.Command{
 			StreamUrl: vidStreamUrl,
 		}
 
-		fmt.Printf("Starting %s...\n", romPath)
+		db, err := rom.BundledDatabase()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error loading bundled ROM database:", err)
+			os.Exit(1)
+		}
 
-		spath := filepath.Join(savePath, util.MD5HashString(romPath), game.GameSavePath)
+		if romDbPath != "" {
+			overrides, err := rom.LoadDatabase(romDbPath)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Error loading ROM database:", err)
+				os.Exit(1)
+			}
 
-		var g game.Game
+			db.Merge(overrides)
+		}
 
-		ok, err := util.FileExists(spath)
+		info, err := rom.Load(romPath, db)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, "Wasn't able to check if a file existed", err)
+			fmt.Fprintln(os.Stderr, "Invalid ROM:", err)
 			os.Exit(1)
 		}
 
-		if ok {
-			fmt.Println("Loading game from save...")
+		if len(patchPaths) > 0 {
+			romPath = patchRom(romPath)
+			title := info.Title
 
-			var save game.Save
-			f, err := os.Open(spath)
+			info, err = rom.Load(romPath, db)
 			if err != nil {
-				fmt.Fprintln(os.Stderr, "Error loading save", err)
+				fmt.Fprintln(os.Stderr, "Invalid patched ROM:", err)
 				os.Exit(1)
 			}
 
-			if err := json.NewDecoder(f).Decode(&save); err != nil {
-				fmt.Fprintln(os.Stderr, "Error decoding game save", err)
-				os.Exit(1)
+			if !info.Known {
+				info.Title = title + " (patched)"
 			}
+		}
+
+		fmt.Printf("Starting %s...\n", info.Title)
+		fmt.Println("ROM:", info.Header)
+		if !info.Known {
+			fmt.Println("ROM isn't in the database. Hash:", info.Hash)
+		}
+
+		retention, err := game.ParseRetentionPolicy(saveRetention)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Invalid save retention policy:", err)
+			os.Exit(1)
+		}
 
-			g, err = game.NewFromSave(save, vid, accessToken)
+		romSaveDir, err := game.RomSaveDir(savePath, romPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error finding saves for ROM:", err)
+			os.Exit(1)
+		}
+
+		spath := filepath.Join(romSaveDir, game.GameSavePath)
+
+		var g game.Game
+
+		save, err := game.LoadSave(spath)
+		if err != nil && !os.IsNotExist(err) {
+			fmt.Fprintln(os.Stderr, "Error loading save", err)
+			os.Exit(1)
+		}
+
+		if err == nil {
+			fmt.Println("Loading game from save...")
+
+			g, err = game.NewFromSave(save, vid, romPath, accessToken)
 			if err != nil {
 				fmt.Fprintln(os.Stderr, "Error creating game:", err)
 				os.Exit(1)
@@ -108,10 +167,110 @@ var playStreamCmd = &cobra.Command{
 			}
 		}
 
+		if memoryMapPath != "" {
+			m, err := emulator.LoadMemoryMap(memoryMapPath)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Error loading memory map:", err)
+				os.Exit(1)
+			}
+
+			if !m.For(g.RomHash) {
+				fmt.Fprintln(os.Stderr, "Memory map isn't for this ROM. Hash:", g.RomHash)
+				os.Exit(1)
+			}
+
+			g.MemoryMap = &m
+
+			if milestonesPath != "" {
+				g.Milestones, err = game.LoadMilestones(milestonesPath, m)
+				if err != nil {
+					fmt.Fprintln(os.Stderr, "Error loading milestones:", err)
+					os.Exit(1)
+				}
+
+				g.PostMilestones = postMilestones
+			}
+
+			var macros []game.Macro
+			if macrosPath != "" {
+				macros, err = game.LoadMacros(macrosPath, m)
+				if err != nil {
+					fmt.Fprintln(os.Stderr, "Error loading macros:", err)
+					os.Exit(1)
+				}
+			}
+
+			if nameEntryPath != "" {
+				g.NameEntry, err = game.LoadNameEntry(nameEntryPath, m)
+				if err != nil {
+					fmt.Fprintln(os.Stderr, "Error loading name entry:", err)
+					os.Exit(1)
+				}
+			}
+
+			if controlsPath != "" {
+				g.ButtonSets, err = game.LoadButtonSets(controlsPath, m, macros)
+				if err != nil {
+					fmt.Fprintln(os.Stderr, "Error loading controls:", err)
+					os.Exit(1)
+				}
+			}
+		} else if milestonesPath != "" || controlsPath != "" || macrosPath != "" || nameEntryPath != "" {
+			fmt.Fprintln(os.Stderr, "Milestones, controls, macros, and name entry need a memory map.")
+			os.Exit(1)
+		}
+
+		if cheatsPath != "" {
+			g.CheatOptions, err = game.LoadCheatOptions(cheatsPath)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Error loading cheats:", err)
+				os.Exit(1)
+			}
+		}
+
+		g.Retention = retention
+		g.Patches = patchPaths
+		g.Obs.Title = info.Title
+
+		if metricsAddr != "" {
+			go serveMetrics()
+		}
+
+		if voteSecret != "" {
+			g.VoteServer = votes.NewServer(voteAddr, voteSecret)
+		}
+
+		if webhookAddr != "" {
+			g.Webhooks = facebook.NewWebhookServer(webhookAddr, webhookAppSecret, webhookVerifyToken)
+		}
+
 		g.Start()
 	},
 }
 
+// Applies the --patch flags to the ROM at romPath and returns the path of the
+// patched ROM.
+func patchRom(romPath string) string {
+	patchedPath, err := rom.PatchFile(romPath, patchPaths, game.PatchedRomDir(savePath))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error patching ROM:", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Applied %d patches to %s\n", len(patchPaths), romPath)
+
+	return patchedPath
+}
+
+// Serves the metrics published through expvar (API usage, poll interval...) at
+// /debug/vars.
+func serveMetrics() {
+	if err := http.ListenAndServe(metricsAddr, nil); err != nil {
+		fmt.Fprintln(os.Stderr, "Error serving metrics:", err)
+		os.Exit(1)
+	}
+}
+
 func init() {
 	RootCmd.AddCommand(streamCmd)
 
@@ -123,4 +282,20 @@ func init() {
 	playStreamCmd.Flags().StringVarP(&vidId, "stream-id", "i", "", "ID of Facebook Live stream to cast to")
 	playStreamCmd.Flags().StringVarP(&vidStreamUrl, "stream-url", "u", "", "URL of Facebook Live stream to cast to")
 	playStreamCmd.Flags().StringVarP(&savePath, "save", "s", "./.saves", "The directory to save the state of the emulator")
+	playStreamCmd.Flags().StringVar(&romDbPath, "rom-db", "", "No-Intro DAT file with ROM titles to add to (or override) the bundled ones")
+	playStreamCmd.Flags().StringSliceVar(&patchPaths, "patch", nil, "IPS or BPS patch to apply to the ROM (can be repeated, applied in order)")
+	playStreamCmd.Flags().StringVar(&saveRetention, "save-retention", game.DefaultRetentionPolicy, "Which save snapshots to keep, as comma separated every:for pairs (omit for to keep forever)")
+	playStreamCmd.Flags().StringVar(&cheatsPath, "cheats", "", "JSON file listing the cheats viewers can vote for (see game.CheatOption)")
+	playStreamCmd.Flags().StringVar(&memoryMapPath, "memory-map", "", "JSON file naming values in the game's memory to watch (see emulator.MemoryMap)")
+	playStreamCmd.Flags().StringVar(&milestonesPath, "milestones", "", "JSON file listing story milestones to announce (needs --memory-map)")
+	playStreamCmd.Flags().StringVar(&controlsPath, "controls", "", "JSON file of button sets to use on particular screens (needs --memory-map, see game.ButtonSet)")
+	playStreamCmd.Flags().StringVar(&macrosPath, "macros", "", "JSON file of input macros --controls can offer viewers (needs --memory-map, see game.Macro)")
+	playStreamCmd.Flags().StringVar(&nameEntryPath, "name-entry", "", "JSON file describing the game's name entry screen, so viewers can vote for names and classes (needs --memory-map, see game.NameEntry)")
+	playStreamCmd.Flags().BoolVar(&postMilestones, "post-milestones", false, "Comment on the stream when a milestone is reached")
+	playStreamCmd.Flags().StringVar(&voteAddr, "vote-addr", ":6263", "Address to accept votes from external systems on")
+	playStreamCmd.Flags().StringVar(&voteSecret, "vote-secret", "", "Shared secret required to submit votes (vote server is disabled if empty)")
+	playStreamCmd.Flags().StringVar(&metricsAddr, "metrics-addr", "", "Address to serve metrics on at /debug/vars (disabled if empty)")
+	playStreamCmd.Flags().StringVar(&webhookAddr, "webhook-addr", "", "Address to receive Page webhooks on instead of polling for comments and reactions (disabled if empty)")
+	playStreamCmd.Flags().StringVar(&webhookAppSecret, "webhook-app-secret", "", "Facebook app secret used to verify webhook signatures")
+	playStreamCmd.Flags().StringVar(&webhookVerifyToken, "webhook-verify-token", "", "Token Facebook must send when subscribing to webhooks")
 }
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/zachlatta/nostalgic-rewind/facebook"
+)
+
+var webhookUrl string
+var webhookUserId string
+var webhookUserName string
+var webhookReaction string
+var webhookRemove bool
+
+var webhookCmd = &cobra.Command{
+	Use:   "webhook",
+	Short: "Work with Facebook Page webhooks",
+}
+
+var sendWebhookCmd = &cobra.Command{
+	Use:   "send [comment message]",
+	Short: "Send a signed test comment or reaction to a local webhook server",
+	Run: func(cmd *cobra.Command, args []string) {
+		if webhookAppSecret == "" {
+			fmt.Fprintln(os.Stderr, "App secret is required to sign the webhook.")
+			os.Exit(1)
+		}
+
+		verb := facebook.WebhookVerbAdd
+		if webhookRemove {
+			verb = facebook.WebhookVerbRemove
+		}
+
+		event := facebook.WebhookEvent{Verb: verb}
+
+		switch {
+		case len(args) == 1:
+			event.Comment = &facebook.Comment{
+				AuthorId:   webhookUserId,
+				AuthorName: webhookUserName,
+				Message:    args[0],
+			}
+		case webhookReaction != "":
+			reactionType, ok := facebook.ReactionTypeForName(webhookReaction)
+			if !ok {
+				fmt.Fprintln(os.Stderr, "Unknown reaction:", webhookReaction)
+				os.Exit(1)
+			}
+
+			event.Reaction = &facebook.Reaction{
+				AuthorId:   webhookUserId,
+				AuthorName: webhookUserName,
+				Type:       reactionType,
+			}
+		default:
+			fmt.Fprintln(os.Stderr, "Please provide either a comment message or a reaction to send.")
+			os.Exit(1)
+		}
+
+		req, err := facebook.NewTestWebhookRequest(webhookUrl, webhookAppSecret, event)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error creating webhook:", err)
+			os.Exit(1)
+		}
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error sending webhook:", err)
+			os.Exit(1)
+		}
+		defer res.Body.Close()
+
+		body, _ := ioutil.ReadAll(res.Body)
+
+		fmt.Println("Response:", res.Status, string(body))
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(webhookCmd)
+
+	webhookCmd.AddCommand(sendWebhookCmd)
+	sendWebhookCmd.Flags().StringVarP(&webhookUrl, "url", "u", "http://localhost:6264/", "URL of the webhook server")
+	sendWebhookCmd.Flags().StringVarP(&webhookAppSecret, "app-secret", "s", "", "Facebook app secret to sign the webhook with")
+	sendWebhookCmd.Flags().StringVar(&webhookUserId, "user-id", "1234", "ID of the user the event is from")
+	sendWebhookCmd.Flags().StringVar(&webhookUserName, "user-name", "Test User", "Name of the user the event is from")
+	sendWebhookCmd.Flags().StringVarP(&webhookReaction, "reaction", "r", "", "Reaction to send (like, love, haha, wow, sad, angry)")
+	sendWebhookCmd.Flags().BoolVar(&webhookRemove, "remove", false, "Send the event as a removal")
+}
@@ -0,0 +1,225 @@
+package emulator
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/paked/nes/nes"
+)
+
+const (
+	ramSize   = 0x0800
+	sramStart = 0x6000
+	sramEnd   = 0x8000
+	prgStart  = 0x8000
+)
+
+// Letters of the Game Genie alphabet, in order of the nibble they stand for.
+const gameGenieLetters = "APZLGITYEOXUKSVN"
+
+// A Game Genie code or a raw RAM poke. Game Genie codes replace what the CPU
+// reads from PRG ROM at Address, but only if the ROM holds Compare there when
+// HasCompare is set. RAM pokes write Value to work RAM or SRAM every frame.
+type Cheat struct {
+	Code string
+
+	Address    uint16
+	Value      byte
+	Compare    byte
+	HasCompare bool
+}
+
+// Parses a 6 or 8 letter Game Genie code, or a RAM poke written as
+// "ADDRESS:VALUE" in hex (e.g. "0075:FF").
+func ParseCheat(code string) (Cheat, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	cheat := Cheat{Code: code}
+
+	if parts := strings.SplitN(code, ":", 2); len(parts) == 2 {
+		address, value := parts[0], parts[1]
+
+		a, err := strconv.ParseUint(address, 16, 16)
+		if err != nil {
+			return cheat, fmt.Errorf("invalid address in %q: %v", code, err)
+		}
+
+		v, err := strconv.ParseUint(value, 16, 8)
+		if err != nil {
+			return cheat, fmt.Errorf("invalid value in %q: %v", code, err)
+		}
+
+		cheat.Address, cheat.Value = uint16(a), byte(v)
+
+		if !cheat.isRAMPoke() {
+			return cheat, fmt.Errorf("%q doesn't poke work RAM or SRAM", code)
+		}
+
+		return cheat, nil
+	}
+
+	if len(code) != 6 && len(code) != 8 {
+		return cheat, fmt.Errorf("%q isn't a Game Genie code or RAM poke", code)
+	}
+
+	n := make([]uint16, len(code))
+	for i, letter := range code {
+		index := strings.IndexRune(gameGenieLetters, letter)
+		if index == -1 {
+			return cheat, fmt.Errorf("%q isn't a Game Genie code", code)
+		}
+
+		n[i] = uint16(index)
+	}
+
+	// See https://tuxnes.sourceforge.net/gamegenie.html
+	cheat.Address = prgStart +
+		((n[3]&7)<<12 | (n[5]&7)<<8 | (n[4]&8)<<8 | (n[2]&7)<<4 | (n[1]&8)<<4 | n[4]&7 | n[3]&8)
+
+	if len(code) == 6 {
+		cheat.Value = byte((n[1]&7)<<4 | (n[0]&8)<<4 | n[0]&7 | n[5]&8)
+	} else {
+		cheat.Value = byte((n[1]&7)<<4 | (n[0]&8)<<4 | n[0]&7 | n[7]&8)
+		cheat.Compare = byte((n[7]&7)<<4 | (n[6]&8)<<4 | n[6]&7 | n[5]&8)
+		cheat.HasCompare = true
+	}
+
+	return cheat, nil
+}
+
+func (c Cheat) isRAMPoke() bool {
+	return c.Address < ramSize || (c.Address >= sramStart && c.Address < sramEnd)
+}
+
+// The PRG ROM bytes a Game Genie code replaced, so they can be put back.
+type romPatch struct {
+	offset   int
+	original byte
+}
+
+// Cheats waiting to be applied on the emulator's thread.
+type cheatState struct {
+	mu      sync.Mutex
+	pending []Cheat
+	changed bool
+
+	// Only touched on the emulator's thread.
+	active  []Cheat
+	patches []romPatch
+}
+
+// Replaces the active cheats. Safe to call from any goroutine; the change
+// takes effect on the next frame.
+func (e *Emulator) SetCheats(cheats []Cheat) {
+	e.cheats.mu.Lock()
+	defer e.cheats.mu.Unlock()
+
+	if sameCheats(cheats, e.cheats.pending) {
+		return
+	}
+
+	e.cheats.pending = append([]Cheat{}, cheats...)
+	e.cheats.changed = true
+}
+
+func sameCheats(a, b []Cheat) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Called before every frame.
+func (e *Emulator) applyCheats(console *nes.Console) {
+	s := &e.cheats
+
+	s.mu.Lock()
+	if s.changed {
+		s.changed = false
+		s.swap(console, s.pending)
+	}
+	s.mu.Unlock()
+
+	for _, cheat := range s.active {
+		if !cheat.isRAMPoke() {
+			continue
+		}
+
+		if cheat.Address < ramSize {
+			console.RAM[cheat.Address] = cheat.Value
+		} else if i := int(cheat.Address - sramStart); i < len(console.Cartridge.SRAM) {
+			console.Cartridge.SRAM[i] = cheat.Value
+		}
+	}
+}
+
+// Puts back the ROM replaced by the old Game Genie codes and patches in the
+// new ones.
+func (s *cheatState) swap(console *nes.Console, cheats []Cheat) {
+	prg := console.Cartridge.PRG
+
+	// In reverse, in case codes overlap.
+	for i := len(s.patches) - 1; i >= 0; i-- {
+		prg[s.patches[i].offset] = s.patches[i].original
+	}
+
+	s.active = cheats
+	s.patches = nil
+
+	for _, cheat := range cheats {
+		if cheat.isRAMPoke() {
+			continue
+		}
+
+		patches := patchPRG(prg, cheat)
+		if len(patches) == 0 {
+			fmt.Fprintf(os.Stderr, "Game Genie code %s doesn't match the ROM, ignoring it\n", cheat.Code)
+		}
+
+		s.patches = append(s.patches, patches...)
+	}
+}
+
+// The mapper decides which bank of PRG ROM the CPU sees at an address, so
+// patch every bank that could be there. Codes without a compare value are only
+// safe on ROMs small enough not to be banked.
+func patchPRG(prg []byte, cheat Cheat) []romPatch {
+	const (
+		unbankedSize = 0x8000
+		bankSize     = 0x2000
+	)
+
+	var patches []romPatch
+
+	if !cheat.HasCompare {
+		if len(prg) > unbankedSize {
+			return nil
+		}
+
+		offset := int(cheat.Address-prgStart) % len(prg)
+		patches = append(patches, romPatch{offset, prg[offset]})
+		prg[offset] = cheat.Value
+
+		return patches
+	}
+
+	for offset := int(cheat.Address-prgStart) % bankSize; offset < len(prg); offset += bankSize {
+		if prg[offset] != cheat.Compare {
+			continue
+		}
+
+		patches = append(patches, romPatch{offset, prg[offset]})
+		prg[offset] = cheat.Value
+	}
+
+	return patches
+}
@@ -2,7 +2,6 @@ package emulator
 
 import (
 	"runtime"
-	"time"
 
 	"github.com/go-gl/gl/v2.1/gl"
 	"github.com/go-gl/glfw/v3.1/glfw"
@@ -30,7 +29,27 @@ type Emulator struct {
 	Director *ui.Director
 
 	Settings Settings
+
+	// Savestate to resume from, if any. Picked by the game, which knows which
+	// generation goes with its own save.
 	savePath string
+
+	// Called on the emulator's thread once the ROM is loaded and the latest
+	// savestate restored, before the first frame runs. Returning an error stops
+	// the emulator, and Play returns it.
+	OnConsoleReady func(console *nes.Console) error
+
+	// Called on the emulator's thread before every frame.
+	OnFrame func(console *nes.Console)
+
+	// Called once the emulator has stopped, if the console was ever ready.
+	OnStop func(console *nes.Console)
+
+	// Set when a hook fails, to be returned by Play.
+	err error
+
+	cheats  cheatState
+	watches watchState
 }
 
 func NewEmulator(settings Settings, controllerOne ui.ControllerAdapter, controllerTwo ui.ControllerAdapter, savePath string) (*Emulator, error) {
@@ -81,17 +100,20 @@ func (e *Emulator) Play(romPath string) error {
 	e.PlayerOneController.SetWindow(window)
 	e.PlayerTwoController.SetWindow(window)
 
-	e.Director = ui.NewDirector(window, audio, e.PlayerOneController, e.PlayerTwoController)
-
-	go func() {
-		time.Sleep(time.Second)
-
-		e.LoadState(e.savePath)
-	}()
+	frames := &frameController{
+		ControllerAdapter: e.PlayerOneController,
+		emulator:          e,
+		window:            window,
+	}
 
+	e.Director = ui.NewDirector(window, audio, frames, e.PlayerTwoController)
 	e.Director.Start([]string{romPath})
 
-	return nil
+	if frames.ready && e.OnStop != nil {
+		e.OnStop(e.console())
+	}
+
+	return e.err
 }
 
 func (e *Emulator) SaveState(path string) error {
@@ -106,6 +128,15 @@ func (e *Emulator) LoadState(path string) error {
 	return c.LoadState(path)
 }
 
+// Does nothing if there's no savestate to resume from.
+func (e *Emulator) loadSavestate() error {
+	if e.savePath == "" {
+		return nil
+	}
+
+	return e.LoadState(e.savePath)
+}
+
 func (e *Emulator) console() *nes.Console {
 	return e.Director.Console()
 }
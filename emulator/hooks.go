@@ -0,0 +1,60 @@
+package emulator
+
+import (
+	"github.com/go-gl/glfw/v3.1/glfw"
+	"github.com/paked/nes/nes"
+	"github.com/paked/nes/ui"
+)
+
+// Wraps player one's controller to find out when frames run. The director
+// reads the controllers at the start of every frame, before stepping the
+// console, which is the only point it calls back into us.
+type frameController struct {
+	ui.ControllerAdapter
+
+	emulator *Emulator
+	window   *glfw.Window
+	ready    bool
+}
+
+func (c *frameController) Buttons() [8]bool {
+	buttons := c.ControllerAdapter.Buttons()
+
+	e := c.emulator
+	console := e.console()
+	if console == nil || e.err != nil {
+		return buttons
+	}
+
+	if !c.ready {
+		if err := e.consoleReady(console); err != nil {
+			e.err = err
+			c.window.SetShouldClose(true)
+			return buttons
+		}
+
+		c.ready = true
+	}
+
+	e.readWatches(console)
+	e.applyCheats(console)
+
+	if e.OnFrame != nil {
+		e.OnFrame(console)
+	}
+
+	return buttons
+}
+
+// Loads the savestate, if there is one, and then calls OnConsoleReady.
+func (e *Emulator) consoleReady(console *nes.Console) error {
+	if err := e.loadSavestate(); err != nil {
+		return err
+	}
+
+	if e.OnConsoleReady != nil {
+		return e.OnConsoleReady(console)
+	}
+
+	return nil
+}
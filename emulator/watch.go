@@ -0,0 +1,279 @@
+package emulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"sync"
+
+	"github.com/paked/nes/nes"
+)
+
+const (
+	WatchUint8  = "u8"
+	WatchUint16 = "u16"
+	WatchUint24 = "u24"
+	WatchUint32 = "u32"
+	WatchBytes  = "bytes"
+)
+
+var watchSizes = map[string]int{
+	WatchUint8:  1,
+	WatchUint16: 2,
+	WatchUint24: 3,
+	WatchUint32: 4,
+}
+
+// Names the interesting parts of a game's memory. Loaded from a JSON file
+// kept for each game, like:
+//
+//	{
+//		"roms": ["<rom hash>"],
+//		"watches": {
+//			"gold": {"address": "601C", "type": "u24"},
+//			"in_battle": {"address": "004B", "type": "u8", "one_of": ["50", "53"]}
+//		}
+//	}
+type MemoryMap struct {
+	// Hashes of the ROMs the map is for (see rom.Hash). Any ROM if empty.
+	Roms []string `json:"roms"`
+
+	Watches map[string]Watch `json:"watches"`
+}
+
+// A value in memory. Numbers are little endian, and are masked and then
+// shifted right so the lowest bit of Mask is the lowest bit of the value.
+type Watch struct {
+	Address hexNumber `json:"address"`
+	Type    string    `json:"type"`
+
+	// Only used by WatchBytes.
+	Length int `json:"length"`
+
+	Mask hexNumber `json:"mask"`
+
+	// If set, the watch is 1 when the value is one of these and 0 when it
+	// isn't. Turns something like the music that's playing into a flag.
+	OneOf []hexNumber `json:"one_of"`
+}
+
+// Numbers are written in hex, like memory maps usually are.
+type hexNumber uint32
+
+func (n *hexNumber) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return err
+	}
+
+	*n = hexNumber(v)
+
+	return nil
+}
+
+func LoadMemoryMap(path string) (MemoryMap, error) {
+	var m MemoryMap
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return m, err
+	}
+
+	if err := json.Unmarshal(data, &m); err != nil {
+		return m, err
+	}
+
+	for name, w := range m.Watches {
+		if err := w.validate(); err != nil {
+			return m, fmt.Errorf("invalid watch %s: %v", name, err)
+		}
+	}
+
+	return m, nil
+}
+
+// Returns whether the map is for the ROM with the given hash.
+func (m MemoryMap) For(romHash string) bool {
+	if len(m.Roms) == 0 {
+		return true
+	}
+
+	for _, hash := range m.Roms {
+		if hash == romHash {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (w Watch) size() int {
+	if w.Type == WatchBytes {
+		return w.Length
+	}
+
+	return watchSizes[w.Type]
+}
+
+func (w Watch) oneOf(v uint32) uint32 {
+	for _, value := range w.OneOf {
+		if uint32(value) == v {
+			return 1
+		}
+	}
+
+	return 0
+}
+
+func (w Watch) validate() error {
+	if _, ok := watchSizes[w.Type]; !ok && w.Type != WatchBytes {
+		return fmt.Errorf("unknown type %q", w.Type)
+	}
+
+	if w.size() < 1 {
+		return fmt.Errorf("bytes watches need a length")
+	}
+
+	if w.Type == WatchBytes && (w.Mask != 0 || len(w.OneOf) > 0) {
+		return fmt.Errorf("bytes watches can't have a mask or values")
+	}
+
+	// Reading anything else, like the PPU's registers, has side effects.
+	start, end := uint32(w.Address), uint32(w.Address)+uint32(w.size())
+	inRAM := end <= ramSize
+	inSRAM := start >= sramStart && end <= sramEnd
+	if !inRAM && !inSRAM {
+		return fmt.Errorf("%04X-%04X isn't in work RAM or SRAM", start, end-1)
+	}
+
+	return nil
+}
+
+// Values of every watch as of the end of a frame.
+type WatchSnapshot struct {
+	Frame uint64
+
+	numbers map[string]uint32
+	bytes   map[string][]byte
+}
+
+// Returns the value of a number watch.
+func (s WatchSnapshot) Uint(name string) (uint32, bool) {
+	v, ok := s.numbers[name]
+	return v, ok
+}
+
+// Returns the value of a bytes watch.
+func (s WatchSnapshot) Bytes(name string) ([]byte, bool) {
+	v, ok := s.bytes[name]
+	return v, ok
+}
+
+// Returns every watch's value, keyed by name.
+func (s WatchSnapshot) Values() map[string]interface{} {
+	values := map[string]interface{}{}
+
+	for name, v := range s.numbers {
+		values[name] = v
+	}
+	for name, v := range s.bytes {
+		values[name] = v
+	}
+
+	return values
+}
+
+type watchState struct {
+	mu       sync.Mutex
+	m        MemoryMap
+	snapshot WatchSnapshot
+}
+
+// Starts evaluating the watches in the memory map every frame. Safe to call
+// from any goroutine.
+func (e *Emulator) SetWatches(m MemoryMap) {
+	e.watches.mu.Lock()
+	defer e.watches.mu.Unlock()
+
+	e.watches.m = m
+}
+
+// Returns the values of the watches as of the latest frame.
+func (e *Emulator) Watches() WatchSnapshot {
+	e.watches.mu.Lock()
+	defer e.watches.mu.Unlock()
+
+	return e.watches.snapshot
+}
+
+// Called before every frame, which is the same as after the last one.
+func (e *Emulator) readWatches(console *nes.Console) {
+	s := &e.watches
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.m.Watches) == 0 {
+		return
+	}
+
+	// Snapshots are handed out, so build a new one rather than changing the
+	// last.
+	snapshot := WatchSnapshot{
+		Frame:   s.snapshot.Frame + 1,
+		numbers: map[string]uint32{},
+		bytes:   map[string][]byte{},
+	}
+
+	for name, w := range s.m.Watches {
+		data := readMemory(console, uint16(w.Address), w.size())
+
+		if w.Type == WatchBytes {
+			snapshot.bytes[name] = data
+			continue
+		}
+
+		var v uint32
+		for i := len(data) - 1; i >= 0; i-- {
+			v = v<<8 | uint32(data[i])
+		}
+
+		if w.Mask != 0 {
+			v &= uint32(w.Mask)
+			for mask := uint32(w.Mask); mask&1 == 0; mask >>= 1 {
+				v >>= 1
+			}
+		}
+
+		if len(w.OneOf) > 0 {
+			v = w.oneOf(v)
+		}
+
+		snapshot.numbers[name] = v
+	}
+
+	s.snapshot = snapshot
+}
+
+func readMemory(console *nes.Console, address uint16, size int) []byte {
+	data := make([]byte, size)
+
+	for i := range data {
+		a := int(address) + i
+
+		switch {
+		case a < ramSize:
+			data[i] = console.RAM[a]
+		case a >= sramStart && a-sramStart < len(console.Cartridge.SRAM):
+			data[i] = console.Cartridge.SRAM[a-sramStart]
+		}
+	}
+
+	return data
+}
@@ -0,0 +1,188 @@
+package facebook
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	fb "github.com/huandu/facebook"
+)
+
+// Everything polled from Facebook about a live video, fetched in one batch
+// request. Each part of the batch can fail on its own, so each has its own
+// error alongside it.
+type VideoPoll struct {
+	Status          string
+	LiveViews       int
+	ReactionSummary map[ReactionType]int
+	VideoErr        error
+
+	// The newest reactions, up to PollOptions.ReactionLimit, unless skipped.
+	// MoreReactions is set if there are older ones that weren't fetched.
+	Reactions     []Reaction
+	MoreReactions bool
+	ReactionsErr  error
+
+	// Only fetched if requested. See PollOptions.
+	Comments       []Comment
+	CommentsCursor string
+	CommentsErr    error
+}
+
+type PollOptions struct {
+	// Set when reactions are received some other way, like webhooks.
+	SkipReactions bool
+
+	// How many of the newest reactions to fetch. Defaults to a full page.
+	ReactionLimit int
+
+	// Whether to fetch comments, and the cursor to fetch them after.
+	Comments       bool
+	CommentsCursor string
+}
+
+// Fetches the video's status, viewer count, reaction summary, and optionally
+// its newest reactions and new comments in a single round trip.
+func PollVideo(id, accessToken string, opts PollOptions) (VideoPoll, error) {
+	session := authedSession(accessToken)
+
+	videoFields := []string{"status", "live_views"}
+	for _, reactionType := range summaryReactionTypes {
+		videoFields = append(videoFields, summaryField(reactionType))
+	}
+
+	requests := []fb.Params{
+		batchRequest(id, fb.Params{
+			"fields": strings.Join(videoFields, ","),
+		}),
+	}
+
+	if !opts.SkipReactions {
+		reactionLimit := reactionPageSize
+		if opts.ReactionLimit > 0 {
+			reactionLimit = opts.ReactionLimit
+		}
+
+		requests = append(requests, batchRequest(fmt.Sprintf("%s/reactions", id), fb.Params{
+			"fields": reactionFields,
+			"limit":  reactionLimit,
+		}))
+	}
+
+	if opts.Comments {
+		commentParams := fb.Params{
+			"fields": commentFields,
+			"filter": "stream",
+			"order":  "chronological",
+		}
+		if opts.CommentsCursor != "" {
+			commentParams["after"] = opts.CommentsCursor
+		}
+
+		requests = append(requests, batchRequest(fmt.Sprintf("%s/comments", id), commentParams))
+	}
+
+	results, err := session.Batch(fb.Params{}, requests...)
+	if err != nil {
+		return VideoPoll{}, err
+	}
+
+	if len(results) != len(requests) {
+		return VideoPoll{}, fmt.Errorf("expected %d batch results, got %d", len(requests), len(results))
+	}
+
+	poll := VideoPoll{CommentsCursor: opts.CommentsCursor}
+
+	if res, err := batchResult(results[0]); err != nil {
+		poll.VideoErr = err
+	} else {
+		poll.VideoErr = parseVideoResult(res, &poll)
+	}
+
+	results = results[1:]
+
+	if !opts.SkipReactions {
+		if res, err := batchResult(results[0]); err != nil {
+			poll.ReactionsErr = err
+		} else {
+			var page pageResult
+			if poll.ReactionsErr = res.Decode(&page); poll.ReactionsErr == nil {
+				poll.Reactions = parseReactions(page.Data)
+				poll.MoreReactions = page.Paging.Next != ""
+			}
+		}
+
+		results = results[1:]
+	}
+
+	if opts.Comments {
+		if res, err := batchResult(results[0]); err != nil {
+			poll.CommentsErr = err
+		} else {
+			var page pageResult
+			if poll.CommentsErr = res.Decode(&page); poll.CommentsErr == nil {
+				poll.Comments, poll.CommentsErr = parseComments(page.Data)
+
+				if page.Paging.Cursors.After != "" {
+					poll.CommentsCursor = page.Paging.Cursors.After
+				}
+			}
+		}
+	}
+
+	return poll, nil
+}
+
+func parseVideoResult(res fb.Result, poll *VideoPoll) error {
+	var video struct {
+		Status    string `facebook:"status"`
+		LiveViews int    `facebook:"live_views"`
+	}
+	if err := res.Decode(&video); err != nil {
+		return err
+	}
+
+	summary, err := parseReactionSummary(res)
+	if err != nil {
+		return err
+	}
+
+	poll.Status = video.Status
+	poll.LiveViews = video.LiveViews
+	poll.ReactionSummary = summary
+
+	return nil
+}
+
+// Builds a GET request for use in a batch.
+func batchRequest(path string, params fb.Params) fb.Params {
+	query := url.Values{}
+	for k, v := range params {
+		query.Set(k, fmt.Sprint(v))
+	}
+
+	return fb.Params{
+		"method":       fb.GET,
+		"relative_url": path + "?" + query.Encode(),
+	}
+}
+
+// Unwraps a single result from a batch, returning an error if that request
+// failed.
+func batchResult(res fb.Result) (fb.Result, error) {
+	batch, err := res.Batch()
+	if err != nil {
+		return nil, err
+	}
+
+	if batch.Result == nil {
+		return nil, errors.New(fmt.Sprintf("batch request failed with status %d", batch.StatusCode))
+	}
+
+	if err := batch.Result.Err(); err != nil {
+		return nil, err
+	}
+
+	return batch.Result, nil
+}
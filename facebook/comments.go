@@ -1,12 +1,41 @@
 package facebook
 
 import (
+	"bufio"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
+	fb "github.com/huandu/facebook"
 	"github.com/zachlatta/nostalgic-rewind/util"
 )
 
+const (
+	baseStreamingUrl = "https://streaming-graph.facebook.com"
+
+	commentFields = "id,created_time,from{id,name},message"
+	commentRate   = "ten_per_second"
+
+	// Only comment IDs are fetched when finding the latest cursor, so pages can
+	// be big.
+	commentCursorPageSize = 1000
+)
+
+// Returned by StreamComments when Facebook won't stream comments for the
+// video, in which case callers should fall back to polling for them with
+// PollVideo.
+var ErrStreamingUnavailable = errors.New("live comment streaming unavailable")
+
+// Comments that are only a sticker or GIF have no message, and are skipped.
+var errNoMessage = errors.New("comment has no message")
+
+// Error codes Facebook uses for rate limits, which pass with time.
+var rateLimitCodes = map[int]bool{4: true, 17: true, 32: true, 613: true}
+
 type Comment struct {
 	Id         string
 	Created    time.Time
@@ -23,24 +52,159 @@ func Comments(id, accessToken string) ([]Comment, error) {
 		return nil, err
 	}
 
-	comments := make([]Comment, len(rawComments))
+	return parseComments(rawComments)
+}
 
-	for i, rawComment := range rawComments {
-		created, err := time.Parse(util.ISO8601, rawComment["created_time"].(string))
-		if err != nil {
-			return nil, err
+// Returns the cursor to pass to PollVideo to only get comments made from now
+// on. Cursors only work with the query that produced them, so this pages
+// through the video's comments in the same order PollVideo does, fetching just
+// their IDs.
+func LatestCommentsCursor(id, accessToken string) (string, error) {
+	session := authedSession(accessToken)
+
+	_, cursor, err := getAllAfter(session, fmt.Sprintf("/%s/comments", id), fb.Params{
+		"fields": "id",
+		"filter": "stream",
+		"order":  "chronological",
+		"limit":  commentCursorPageSize,
+	}, "")
+
+	return cursor, err
+}
+
+// Connects to Facebook's live comment stream for the video and sends comments
+// down the given channel as they're made. Blocks until the stream closes.
+func StreamComments(id, accessToken string, comments chan<- Comment) error {
+	streamUrl, _ := url.Parse(fmt.Sprintf("%s/%s/live_comments", baseStreamingUrl, id))
+	query := streamUrl.Query()
+
+	query.Add("access_token", accessToken)
+	query.Add("comment_rate", commentRate)
+	query.Add("fields", commentFields)
+
+	streamUrl.RawQuery = query.Encode()
+
+	res, err := http.Get(streamUrl.String())
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return streamError(res)
+	}
+
+	// Server-sent events are newline delimited. We only care about the data
+	// lines, which each hold a single comment.
+	scanner := bufio.NewScanner(res.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
 		}
 
-		authorInfo := rawComment["from"].(map[string]interface{})
+		var rawComment map[string]interface{}
+		if err := json.Unmarshal([]byte(strings.TrimSpace(strings.TrimPrefix(line, "data:"))), &rawComment); err != nil {
+			return err
+		}
+
+		comment, err := parseComment(rawComment)
+		if err == errNoMessage {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		comments <- comment
+	}
+
+	return scanner.Err()
+}
+
+// Only a client error that isn't a rate limit means Facebook won't stream the
+// video's comments. Anything else is worth retrying.
+func streamError(res *http.Response) error {
+	var body struct {
+		Error struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	json.NewDecoder(res.Body).Decode(&body)
+
+	clientErr := res.StatusCode >= 400 && res.StatusCode < 500
+	if clientErr && res.StatusCode != http.StatusTooManyRequests && !rateLimitCodes[body.Error.Code] {
+		return ErrStreamingUnavailable
+	}
+
+	return fmt.Errorf("comment stream responded with %s: %s", res.Status, body.Error.Message)
+}
+
+func parseComments(rawComments []fb.Result) ([]Comment, error) {
+	comments := make([]Comment, 0, len(rawComments))
 
-		comments[i] = Comment{
-			Id:         rawComment["id"].(string),
-			Created:    created,
-			AuthorId:   authorInfo["id"].(string),
-			AuthorName: authorInfo["name"].(string),
-			Message:    rawComment["message"].(string),
+	for _, rawComment := range rawComments {
+		comment, err := parseComment(rawComment)
+		if err == errNoMessage {
+			continue
+		} else if err != nil {
+			return nil, err
 		}
+
+		comments = append(comments, comment)
 	}
 
 	return comments, nil
 }
+
+func parseComment(rawComment map[string]interface{}) (Comment, error) {
+	id, _ := rawComment["id"].(string)
+	createdTime, _ := rawComment["created_time"].(string)
+	if id == "" || createdTime == "" {
+		return Comment{}, errors.New("comment is missing its ID or creation time")
+	}
+
+	message, _ := rawComment["message"].(string)
+	if message == "" {
+		return Comment{}, errNoMessage
+	}
+
+	created, err := time.Parse(util.ISO8601, createdTime)
+	if err != nil {
+		return Comment{}, err
+	}
+
+	comment := Comment{
+		Id:      id,
+		Created: created,
+		Message: message,
+	}
+
+	// Authors are left out when they haven't granted the app permission to see
+	// them.
+	if authorInfo, ok := rawComment["from"].(map[string]interface{}); ok {
+		comment.AuthorId, _ = authorInfo["id"].(string)
+		comment.AuthorName, _ = authorInfo["name"].(string)
+	}
+
+	return comment, nil
+}
+
+// Comments on the video as the page the access token is for. Returns the ID
+// of the new comment.
+func PostComment(id, accessToken, message string) (string, error) {
+	session := authedSession(accessToken)
+
+	res, err := session.Post(fmt.Sprintf("/%s/comments", id), fb.Params{
+		"message": message,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	commentId, _ := res["id"].(string)
+
+	return commentId, nil
+}
@@ -2,6 +2,14 @@ package facebook
 
 import (
 	"fmt"
+	"strings"
+
+	fb "github.com/huandu/facebook"
+)
+
+const (
+	reactionFields   = "id,name,type"
+	reactionPageSize = 500
 )
 
 type ReactionType int
@@ -16,6 +24,16 @@ const (
 	ReactionThankful
 )
 
+// Reaction types that are counted in VideoPoll.ReactionSummary.
+var summaryReactionTypes = []ReactionType{
+	ReactionLike,
+	ReactionLove,
+	ReactionHaha,
+	ReactionWow,
+	ReactionSad,
+	ReactionAngry,
+}
+
 type Reaction struct {
 	AuthorId   string
 	AuthorName string
@@ -24,11 +42,72 @@ type Reaction struct {
 
 func Reactions(id, accessToken string) ([]Reaction, error) {
 	session := authedSession(accessToken)
-	rawReacts, err := getAllPaginated(session, fmt.Sprintf("/%s/reactions", id), nil)
+	rawReacts, err := getAllPaginated(session, fmt.Sprintf("/%s/reactions", id), fb.Params{
+		"fields": reactionFields,
+		"limit":  reactionPageSize,
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	return parseReactions(rawReacts), nil
+}
+
+func parseReactionSummary(res fb.Result) (map[ReactionType]int, error) {
+	summary := map[ReactionType]int{}
+
+	for _, reactionType := range summaryReactionTypes {
+		var count int
+		if err := res.DecodeField(summaryAlias(reactionType)+".summary.total_count", &count); err != nil {
+			return nil, err
+		}
+
+		summary[reactionType] = count
+	}
+
+	return summary, nil
+}
+
+// Returns the reactions that differ from the given known reactions (keyed by
+// user ID). Reactions come back newest first, so pages are fetched until one
+// has no changes on it, which for a big audience is usually just the first.
+//
+// Removed reactions can't be detected this way. Compare the total from
+// VideoPoll.ReactionSummary to catch those and fall back to Reactions.
+func ChangedReactions(id, accessToken string, known map[string]Reaction) ([]Reaction, error) {
+	session := authedSession(accessToken)
+	path := fmt.Sprintf("/%s/reactions", id)
+	params := fb.Params{
+		"fields": reactionFields,
+		"limit":  reactionPageSize,
+	}
+
+	var changed []Reaction
+	var cursor string
+
+	for {
+		rawReacts, after, hasNext, err := getPage(session, path, params, cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		pageChanged := false
+		for _, reaction := range parseReactions(rawReacts) {
+			if known[reaction.AuthorId] != reaction {
+				changed = append(changed, reaction)
+				pageChanged = true
+			}
+		}
+
+		if !pageChanged || !hasNext {
+			return changed, nil
+		}
+
+		cursor = after
+	}
+}
+
+func parseReactions(rawReacts []fb.Result) []Reaction {
 	reacts := make([]Reaction, len(rawReacts))
 
 	for i, rawReact := range rawReacts {
@@ -39,7 +118,41 @@ func Reactions(id, accessToken string) ([]Reaction, error) {
 		}
 	}
 
-	return reacts, nil
+	return reacts
+}
+
+// Whether or not reactions of this type are counted in
+// VideoPoll.ReactionSummary.
+func (t ReactionType) Summarized() bool {
+	for _, reactionType := range summaryReactionTypes {
+		if t == reactionType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Field that gets the total count of the given reaction type, without
+// downloading any of the reactions themselves.
+func summaryField(reactionType ReactionType) string {
+	return fmt.Sprintf(
+		"reactions.type(%s).limit(0).summary(total_count).as(%s)",
+		nameForReactionType(reactionType),
+		summaryAlias(reactionType),
+	)
+}
+
+// Returns the reaction type with the given name (case insensitive) and
+// whether or not it exists.
+func ReactionTypeForName(name string) (ReactionType, bool) {
+	reactionType := reactionTypeForName(strings.ToUpper(name))
+
+	return reactionType, reactionType != -1
+}
+
+func summaryAlias(reactionType ReactionType) string {
+	return "reactions_" + strings.ToLower(nameForReactionType(reactionType))
 }
 
 func reactionTypeForName(reactionName string) ReactionType {
@@ -62,3 +175,24 @@ func reactionTypeForName(reactionName string) ReactionType {
 		return -1
 	}
 }
+
+func nameForReactionType(reactionType ReactionType) string {
+	switch reactionType {
+	case ReactionLike:
+		return "LIKE"
+	case ReactionLove:
+		return "LOVE"
+	case ReactionHaha:
+		return "HAHA"
+	case ReactionWow:
+		return "WOW"
+	case ReactionSad:
+		return "SAD"
+	case ReactionAngry:
+		return "ANGRY"
+	case ReactionThankful:
+		return "THANKFUL"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,129 @@
+package facebook
+
+import (
+	"encoding/json"
+	"expvar"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// Percentages (0-100) of the rate limit quota that has been used up, as
+// reported by Facebook in the X-App-Usage and X-Page-Usage headers.
+type Usage struct {
+	CallCount    int `json:"call_count"`
+	TotalCPUTime int `json:"total_cputime"`
+	TotalTime    int `json:"total_time"`
+}
+
+// Returns the percentage used of whichever quota is closest to its limit.
+func (u Usage) Max() int {
+	max := u.CallCount
+	if u.TotalCPUTime > max {
+		max = u.TotalCPUTime
+	}
+	if u.TotalTime > max {
+		max = u.TotalTime
+	}
+
+	return max
+}
+
+var (
+	usageMutex sync.Mutex
+	appUsage   Usage
+	pageUsage  Usage
+
+	// Published so current usage can be watched through /debug/vars.
+	usageVars = expvar.NewMap("facebook_usage")
+)
+
+// Returns the most recently reported app and page usage.
+func CurrentUsage() (app Usage, page Usage) {
+	usageMutex.Lock()
+	defer usageMutex.Unlock()
+
+	return appUsage, pageUsage
+}
+
+// Returns the percentage used of whichever app or page quota is closest to its
+// limit.
+func MaxUsage() int {
+	app, page := CurrentUsage()
+
+	if app.Max() > page.Max() {
+		return app.Max()
+	}
+
+	return page.Max()
+}
+
+// Records the usage headers on every response from the Graph API.
+type usageTrackingClient struct {
+	client *http.Client
+}
+
+var usageClient = &usageTrackingClient{client: http.DefaultClient}
+
+func (c *usageTrackingClient) Do(req *http.Request) (*http.Response, error) {
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	recordUsage(res.Header)
+
+	return res, nil
+}
+
+func (c *usageTrackingClient) Get(url string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Do(req)
+}
+
+func (c *usageTrackingClient) Post(url string, bodyType string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequest("POST", url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", bodyType)
+
+	return c.Do(req)
+}
+
+func recordUsage(header http.Header) {
+	usageMutex.Lock()
+	defer usageMutex.Unlock()
+
+	if raw := header.Get("X-App-Usage"); raw != "" {
+		var usage Usage
+		if err := json.Unmarshal([]byte(raw), &usage); err == nil {
+			appUsage = usage
+			publishUsage("app", usage)
+		}
+	}
+
+	if raw := header.Get("X-Page-Usage"); raw != "" {
+		var usage Usage
+		if err := json.Unmarshal([]byte(raw), &usage); err == nil {
+			pageUsage = usage
+			publishUsage("page", usage)
+		}
+	}
+}
+
+func publishUsage(prefix string, usage Usage) {
+	for name, value := range map[string]int{
+		"call_count":    usage.CallCount,
+		"total_cputime": usage.TotalCPUTime,
+		"total_time":    usage.TotalTime,
+	} {
+		v := new(expvar.Int)
+		v.Set(int64(value))
+		usageVars.Set(prefix+"_"+name, v)
+	}
+}
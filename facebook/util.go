@@ -8,6 +8,9 @@ func authedSession(accessToken string) *fb.Session {
 	session := fb.Session{}
 	session.SetAccessToken(accessToken)
 
+	// Keep track of how close we are to being rate limited.
+	session.HttpClient = usageClient
+
 	return &session
 }
 
@@ -33,3 +36,63 @@ func getAllPaginated(session *fb.Session, path string, params fb.Params) ([]fb.R
 
 	return paging.Data(), nil
 }
+
+// Fetches every page of results after the given cursor (or from the start if
+// the cursor is empty) and returns them along with the cursor of the last
+// result, which can be passed back in to only get results added since.
+func getAllAfter(session *fb.Session, path string, params fb.Params, cursor string) ([]fb.Result, string, error) {
+	var results []fb.Result
+
+	for {
+		data, after, hasNext, err := getPage(session, path, params, cursor)
+		if err != nil {
+			return nil, cursor, err
+		}
+
+		results = append(results, data...)
+
+		// Empty pages don't come with cursors, so hold on to the last one we saw.
+		if after != "" {
+			cursor = after
+		}
+
+		if !hasNext {
+			return results, cursor, nil
+		}
+	}
+}
+
+// A single page of results from an edge like /{id}/reactions.
+type pageResult struct {
+	Data   []fb.Result `facebook:"data"`
+	Paging struct {
+		Cursors struct {
+			After string `facebook:"after"`
+		} `facebook:"cursors"`
+		Next string `facebook:"next"`
+	} `facebook:"paging"`
+}
+
+// Fetches a single page of results after the given cursor, returning the
+// cursor to the page after it and whether or not there is one.
+func getPage(session *fb.Session, path string, params fb.Params, cursor string) (data []fb.Result, after string, hasNext bool, err error) {
+	pageParams := fb.Params{}
+	for k, v := range params {
+		pageParams[k] = v
+	}
+	if cursor != "" {
+		pageParams["after"] = cursor
+	}
+
+	res, err := session.Get(path, pageParams)
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	var page pageResult
+	if err := res.Decode(&page); err != nil {
+		return nil, "", false, err
+	}
+
+	return page.Data, page.Paging.Cursors.After, page.Paging.Next != "", nil
+}
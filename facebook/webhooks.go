@@ -0,0 +1,268 @@
+package facebook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	WebhookVerbAdd    = "add"
+	WebhookVerbEdit   = "edited"
+	WebhookVerbRemove = "remove"
+
+	// Events waiting for the game to take them. Callbacks wait for room once
+	// this many are queued.
+	webhookQueueSize = 1000
+)
+
+// A comment or reaction delivered by a Page webhook. Exactly one of Comment
+// and Reaction is set.
+type WebhookEvent struct {
+	Verb   string
+	PostId string
+
+	Comment  *Comment
+	Reaction *Reaction
+}
+
+// Receives Page webhook callbacks for feed and live video comments and
+// reactions and sends them down Events. Subscriptions are verified with
+// VerifyToken, and every callback must be signed with the app secret.
+//
+// Events are queued in the order their callbacks arrive, so a reaction that's
+// added and then removed is never seen the other way around.
+type WebhookServer struct {
+	Addr        string
+	AppSecret   string
+	VerifyToken string
+
+	Events chan WebhookEvent
+
+	// Held while a callback's events are queued, so callbacks don't interleave.
+	queueMu sync.Mutex
+}
+
+// The parts of a webhook payload we care about. See
+// https://developers.facebook.com/docs/graph-api/webhooks/reference/page
+type webhookPayload struct {
+	Object string         `json:"object"`
+	Entry  []webhookEntry `json:"entry"`
+}
+
+type webhookEntry struct {
+	Id      string          `json:"id"`
+	Time    int64           `json:"time"`
+	Changes []webhookChange `json:"changes"`
+}
+
+type webhookChange struct {
+	Field string             `json:"field"`
+	Value webhookChangeValue `json:"value"`
+}
+
+type webhookChangeValue struct {
+	Item         string `json:"item"`
+	Verb         string `json:"verb"`
+	PostId       string `json:"post_id"`
+	CommentId    string `json:"comment_id,omitempty"`
+	Message      string `json:"message,omitempty"`
+	ReactionType string `json:"reaction_type,omitempty"`
+	CreatedTime  int64  `json:"created_time"`
+	From         struct {
+		Id   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"from"`
+}
+
+func NewWebhookServer(addr, appSecret, verifyToken string) *WebhookServer {
+	return &WebhookServer{
+		Addr:        addr,
+		AppSecret:   appSecret,
+		VerifyToken: verifyToken,
+		Events:      make(chan WebhookEvent, webhookQueueSize),
+	}
+}
+
+func (s *WebhookServer) ListenAndServe() error {
+	if s.AppSecret == "" || s.VerifyToken == "" {
+		return errors.New("an app secret and verify token are required to receive webhooks")
+	}
+
+	srv := http.Server{Addr: s.Addr, Handler: s}
+
+	return srv.ListenAndServe()
+}
+
+func (s *WebhookServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.verifySubscription(w, r)
+	case http.MethodPost:
+		s.receive(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// Facebook makes a GET request with a challenge when the webhook is
+// subscribed, which we have to echo back if the verify token matches.
+func (s *WebhookServer) verifySubscription(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	if params.Get("hub.mode") != "subscribe" || !hmac.Equal([]byte(params.Get("hub.verify_token")), []byte(s.VerifyToken)) {
+		http.Error(w, "invalid verify token", http.StatusForbidden)
+		return
+	}
+
+	fmt.Fprint(w, params.Get("hub.challenge"))
+}
+
+func (s *WebhookServer) receive(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "error reading body", http.StatusBadRequest)
+		return
+	}
+
+	if !validWebhookSignature(s.AppSecret, body, r.Header) {
+		http.Error(w, "invalid signature", http.StatusForbidden)
+		return
+	}
+
+	var payload webhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	s.queueMu.Lock()
+	for _, event := range payload.events() {
+		s.Events <- event
+	}
+	s.queueMu.Unlock()
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// Returns the comment and reaction events in the payload, skipping any other
+// changes to the page.
+func (p webhookPayload) events() []WebhookEvent {
+	var events []WebhookEvent
+
+	for _, entry := range p.Entry {
+		for _, change := range entry.Changes {
+			if change.Field != "feed" && change.Field != "live_videos" {
+				continue
+			}
+
+			v := change.Value
+			event := WebhookEvent{Verb: v.Verb, PostId: v.PostId}
+
+			switch v.Item {
+			case "comment":
+				event.Comment = &Comment{
+					Id:         v.CommentId,
+					Created:    time.Unix(v.CreatedTime, 0),
+					AuthorId:   v.From.Id,
+					AuthorName: v.From.Name,
+					Message:    v.Message,
+				}
+			case "reaction":
+				event.Reaction = &Reaction{
+					AuthorId:   v.From.Id,
+					AuthorName: v.From.Name,
+					Type:       reactionTypeForName(strings.ToUpper(v.ReactionType)),
+				}
+			default:
+				continue
+			}
+
+			events = append(events, event)
+		}
+	}
+
+	return events
+}
+
+// Checks the X-Hub-Signature-256 header if it's set and X-Hub-Signature (an
+// HMAC-SHA1 of the body) otherwise.
+func validWebhookSignature(appSecret string, body []byte, header http.Header) bool {
+	if signature := header.Get("X-Hub-Signature-256"); signature != "" {
+		return hmac.Equal([]byte(signature), []byte(signWebhook(sha256.New, "sha256=", appSecret, body)))
+	}
+
+	signature := header.Get("X-Hub-Signature")
+
+	return signature != "" && hmac.Equal([]byte(signature), []byte(SignWebhookPayload(appSecret, body)))
+}
+
+// Returns the X-Hub-Signature header Facebook would send with the given body.
+func SignWebhookPayload(appSecret string, body []byte) string {
+	return signWebhook(sha1.New, "sha1=", appSecret, body)
+}
+
+func signWebhook(h func() hash.Hash, prefix, appSecret string, body []byte) string {
+	mac := hmac.New(h, []byte(appSecret))
+	mac.Write(body)
+
+	return prefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Builds a signed webhook callback for the given event, exactly like one
+// Facebook would send. Used to test webhook servers locally.
+func NewTestWebhookRequest(url, appSecret string, event WebhookEvent) (*http.Request, error) {
+	value := webhookChangeValue{
+		Verb:        event.Verb,
+		PostId:      event.PostId,
+		CreatedTime: time.Now().Unix(),
+	}
+
+	switch {
+	case event.Comment != nil:
+		value.Item = "comment"
+		value.CommentId = event.Comment.Id
+		value.Message = event.Comment.Message
+		value.From.Id = event.Comment.AuthorId
+		value.From.Name = event.Comment.AuthorName
+	case event.Reaction != nil:
+		value.Item = "reaction"
+		value.ReactionType = strings.ToLower(nameForReactionType(event.Reaction.Type))
+		value.From.Id = event.Reaction.AuthorId
+		value.From.Name = event.Reaction.AuthorName
+	default:
+		return nil, errors.New("event must have a comment or reaction")
+	}
+
+	body, err := json.Marshal(webhookPayload{
+		Object: "page",
+		Entry: []webhookEntry{{
+			Time:    time.Now().Unix(),
+			Changes: []webhookChange{{Field: "feed", Value: value}},
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Hub-Signature", SignWebhookPayload(appSecret, body))
+
+	return req, nil
+}
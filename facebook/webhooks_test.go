@@ -0,0 +1,194 @@
+package facebook
+
+import (
+	"crypto/sha256"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const (
+	testAppSecret   = "app-secret"
+	testVerifyToken = "verify-token"
+)
+
+func serveWebhook(s *WebhookServer, req *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestVerifySubscription(t *testing.T) {
+	tests := []struct {
+		name   string
+		mode   string
+		token  string
+		status int
+	}{
+		{"valid", "subscribe", testVerifyToken, http.StatusOK},
+		{"wrong token", "subscribe", "wrong", http.StatusForbidden},
+		{"wrong mode", "unsubscribe", testVerifyToken, http.StatusForbidden},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewWebhookServer("", testAppSecret, testVerifyToken)
+
+			query := url.Values{}
+			query.Set("hub.mode", test.mode)
+			query.Set("hub.verify_token", test.token)
+			query.Set("hub.challenge", "1158201444")
+
+			rec := serveWebhook(s, httptest.NewRequest(http.MethodGet, "/?"+query.Encode(), nil))
+			if rec.Code != test.status {
+				t.Fatalf("expected status %d, got %d", test.status, rec.Code)
+			}
+
+			if test.status == http.StatusOK && rec.Body.String() != "1158201444" {
+				t.Errorf("expected the challenge to be echoed, got %q", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestWebhookSignature(t *testing.T) {
+	body := []byte(`{"object": "page", "entry": []}`)
+
+	tests := []struct {
+		name   string
+		header http.Header
+		valid  bool
+	}{
+		{"sha256", http.Header{"X-Hub-Signature-256": {signWebhook(sha256.New, "sha256=", testAppSecret, body)}}, true},
+		{"sha1", http.Header{"X-Hub-Signature": {SignWebhookPayload(testAppSecret, body)}}, true},
+		{"sha256 wrong secret", http.Header{"X-Hub-Signature-256": {signWebhook(sha256.New, "sha256=", "wrong", body)}}, false},
+		{"sha1 wrong secret", http.Header{"X-Hub-Signature": {SignWebhookPayload("wrong", body)}}, false},
+		{
+			"sha256 checked over sha1",
+			http.Header{
+				"X-Hub-Signature-256": {signWebhook(sha256.New, "sha256=", "wrong", body)},
+				"X-Hub-Signature":     {SignWebhookPayload(testAppSecret, body)},
+			},
+			false,
+		},
+		{"sha1 sent as sha256", http.Header{"X-Hub-Signature-256": {SignWebhookPayload(testAppSecret, body)}}, false},
+		{"missing", http.Header{}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if valid := validWebhookSignature(testAppSecret, body, test.header); valid != test.valid {
+				t.Errorf("expected valid to be %t, got %t", test.valid, valid)
+			}
+		})
+	}
+}
+
+func TestWebhookRejectsUnsignedCallbacks(t *testing.T) {
+	s := NewWebhookServer("", testAppSecret, testVerifyToken)
+
+	req, err := NewTestWebhookRequest("/", "wrong", WebhookEvent{
+		Verb:    WebhookVerbAdd,
+		Comment: &Comment{Id: "1", AuthorId: "1001", Message: "!cheat gil"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rec := serveWebhook(s, req); rec.Code != http.StatusForbidden {
+		t.Errorf("expected status %d, got %d", http.StatusForbidden, rec.Code)
+	}
+
+	if len(s.Events) != 0 {
+		t.Error("expected no events from an unsigned callback")
+	}
+}
+
+func TestNewTestWebhookRequest(t *testing.T) {
+	s := NewWebhookServer("", testAppSecret, testVerifyToken)
+
+	comment := Comment{Id: "10_20", AuthorId: "1001", AuthorName: "Ada Lovelace", Message: "up"}
+	reaction := Reaction{AuthorId: "1002", AuthorName: "Alan Turing", Type: ReactionAngry}
+
+	events := []WebhookEvent{
+		{Verb: WebhookVerbAdd, PostId: "10", Comment: &comment},
+		{Verb: WebhookVerbAdd, PostId: "10", Reaction: &reaction},
+	}
+
+	for _, event := range events {
+		req, err := NewTestWebhookRequest("/", testAppSecret, event)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if rec := serveWebhook(s, req); rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body)
+		}
+	}
+
+	got := <-s.Events
+	if got.Verb != WebhookVerbAdd || got.PostId != "10" || got.Comment == nil || got.Reaction != nil {
+		t.Fatalf("expected a comment event, got %+v", got)
+	}
+
+	got.Comment.Created = comment.Created
+	if *got.Comment != comment {
+		t.Errorf("expected comment %+v, got %+v", comment, *got.Comment)
+	}
+
+	got = <-s.Events
+	if got.Reaction == nil || got.Comment != nil || *got.Reaction != reaction {
+		t.Errorf("expected reaction %+v, got %+v", reaction, got)
+	}
+
+	if _, err := NewTestWebhookRequest("/", testAppSecret, WebhookEvent{Verb: WebhookVerbAdd}); err == nil {
+		t.Error("expected an error for an event without a comment or reaction")
+	}
+}
+
+func TestWebhookEventOrder(t *testing.T) {
+	s := NewWebhookServer("", testAppSecret, testVerifyToken)
+
+	verbs := []string{WebhookVerbAdd, WebhookVerbRemove, WebhookVerbAdd, WebhookVerbRemove}
+
+	for _, verb := range verbs {
+		req, err := NewTestWebhookRequest("/", testAppSecret, WebhookEvent{
+			Verb:     verb,
+			Reaction: &Reaction{AuthorId: "1001", Type: ReactionLike},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		serveWebhook(s, req)
+	}
+
+	for i, verb := range verbs {
+		if event := <-s.Events; event.Verb != verb {
+			t.Errorf("expected event %d to be %s, got %s", i+1, verb, event.Verb)
+		}
+	}
+}
+
+func TestWebhookSkipsOtherChanges(t *testing.T) {
+	s := NewWebhookServer("", testAppSecret, testVerifyToken)
+
+	body := `{"object": "page", "entry": [{"changes": [
+		{"field": "feed", "value": {"item": "status", "verb": "add"}},
+		{"field": "ratings", "value": {"item": "comment", "verb": "add"}}
+	]}]}`
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set("X-Hub-Signature", SignWebhookPayload(testAppSecret, []byte(body)))
+
+	if rec := serveWebhook(s, req); rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	if len(s.Events) != 0 {
+		t.Errorf("expected no events, got %d", len(s.Events))
+	}
+}
@@ -0,0 +1,218 @@
+package game
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/zachlatta/nostalgic-rewind/util"
+)
+
+const archiveMetadataName = "metadata.json"
+
+// Describes the contents of a save archive. Stored as the first file in the
+// archive.
+type ArchiveMetadata struct {
+	// Name of the ROM save directory the saves came from, which identifies the
+	// ROM they belong to.
+	RomHash    string    `json:"rom_hash"`
+	RomPath    string    `json:"rom_path"`
+	AppVersion string    `json:"app_version"`
+	ExportedAt time.Time `json:"exported_at"`
+	SavedAt    time.Time `json:"saved_at"`
+
+	// SHA-256 checksum of every file in the archive, keyed by name.
+	Files map[string]string `json:"files"`
+}
+
+// Bundles every save file in the given ROM save directory (the emulator state,
+// game state, and anything else kept alongside them) into a gzipped tarball
+// written to w.
+func ExportArchive(w io.Writer, romSaveDir, romPath, appVersion string) (ArchiveMetadata, error) {
+	metadata := ArchiveMetadata{
+		RomHash:    filepath.Base(romSaveDir),
+		RomPath:    romPath,
+		AppVersion: appVersion,
+		ExportedAt: time.Now(),
+		Files:      map[string]string{},
+	}
+
+	entries, err := ioutil.ReadDir(romSaveDir)
+	if err != nil {
+		return metadata, err
+	}
+
+	files := map[string][]byte{}
+
+	for _, entry := range entries {
+		name := entry.Name()
+
+		// Snapshots, the previous generation, and half written files aren't part
+		// of the current save, and checksums are recomputed on import.
+		if entry.IsDir() || strings.HasSuffix(name, util.PreviousExtension) || strings.HasSuffix(name, util.TmpExtension) || strings.Contains(name, util.ChecksumExtension) {
+			continue
+		}
+
+		path, err := util.LatestValidFile(filepath.Join(romSaveDir, name))
+		if err != nil {
+			return metadata, err
+		}
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return metadata, err
+		}
+
+		files[name] = data
+		metadata.Files[name] = checksum(data)
+
+		if name == GameSavePath {
+			metadata.SavedAt = entry.ModTime()
+		}
+	}
+
+	for _, required := range snapshotFiles {
+		if _, ok := files[required]; !ok {
+			return metadata, fmt.Errorf("%s is missing from %s", required, romSaveDir)
+		}
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	metadataJson, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return metadata, err
+	}
+
+	if err := writeTarFile(tw, archiveMetadataName, metadataJson, metadata.ExportedAt); err != nil {
+		return metadata, err
+	}
+
+	for name, data := range files {
+		if err := writeTarFile(tw, name, data, metadata.ExportedAt); err != nil {
+			return metadata, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return metadata, err
+	}
+
+	return metadata, gz.Close()
+}
+
+// Reads a save archive created by ExportArchive and, once every file in it has
+// been checked against the metadata, writes them into the given ROM save
+// directory. Saves already there are kept as the previous generation. Unless
+// force is set, the archive must be for the same ROM.
+func ImportArchive(r io.Reader, romSaveDir string, force bool) (ArchiveMetadata, error) {
+	var metadata ArchiveMetadata
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return metadata, err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	files := map[string][]byte{}
+	foundMetadata := false
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return metadata, err
+		}
+
+		// Archives only ever hold flat files. Refuse anything that could be
+		// written outside of the save directory.
+		if header.Name != filepath.Base(header.Name) || header.Name == ".." {
+			return metadata, fmt.Errorf("archive contains invalid path %q", header.Name)
+		}
+
+		data, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return metadata, err
+		}
+
+		if header.Name == archiveMetadataName {
+			if err := json.Unmarshal(data, &metadata); err != nil {
+				return metadata, err
+			}
+			foundMetadata = true
+			continue
+		}
+
+		files[header.Name] = data
+	}
+
+	if !foundMetadata {
+		return metadata, errors.New("archive has no metadata")
+	}
+
+	if expected := filepath.Base(romSaveDir); metadata.RomHash != expected && !force {
+		return metadata, fmt.Errorf("archive is for ROM %s (%s), not %s", metadata.RomHash, metadata.RomPath, expected)
+	}
+
+	for name, expected := range metadata.Files {
+		data, ok := files[name]
+		if !ok {
+			return metadata, fmt.Errorf("%s is missing from the archive", name)
+		}
+
+		if checksum(data) != expected {
+			return metadata, fmt.Errorf("checksum mismatch for %s, the archive is corrupt", name)
+		}
+	}
+
+	for name := range files {
+		if _, ok := metadata.Files[name]; !ok {
+			return metadata, fmt.Errorf("%s isn't listed in the archive's metadata", name)
+		}
+	}
+
+	// Make sure this build can actually load the save before replacing anything.
+	if _, err := DecodeSave(files[GameSavePath]); err != nil {
+		return metadata, err
+	}
+
+	for name, data := range files {
+		if err := util.WriteDataSafely(filepath.Join(romSaveDir, name), data); err != nil {
+			return metadata, err
+		}
+	}
+
+	return metadata, nil
+}
+
+func writeTarFile(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}
+
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+
+	_, err := tw.Write(data)
+
+	return err
+}
+
+func checksum(data []byte) string {
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
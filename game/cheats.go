@@ -0,0 +1,234 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zachlatta/nostalgic-rewind/emulator"
+)
+
+const (
+	// Comments starting with this vote for the cheat named after it.
+	cheatVotePrefix = "!cheat"
+
+	// Votes older than this don't count towards activating a cheat.
+	cheatVoteWindow = 5 * time.Minute
+
+	// Most cheats listed on the overlay.
+	maxOverlayCheats = 3
+)
+
+// A cheat viewers can vote to turn on for a while.
+type CheatOption struct {
+	Name string `json:"name"`
+
+	// Game Genie codes or RAM pokes. See emulator.ParseCheat.
+	Codes []string `json:"codes"`
+
+	// How long the cheat stays on once activated, like "5m".
+	Duration string `json:"duration"`
+
+	// Number of different viewers who have to vote for the cheat within
+	// cheatVoteWindow to activate it.
+	Votes int `json:"votes"`
+
+	duration time.Duration
+	cheats   []emulator.Cheat
+}
+
+// A cheat that's been voted on, as kept in the save.
+type ActiveCheat struct {
+	Name    string    `json:"name"`
+	Expires time.Time `json:"expires"`
+}
+
+// Loads the cheats viewers can vote for from a JSON list of CheatOptions.
+func LoadCheatOptions(path string) ([]CheatOption, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var options []CheatOption
+	if err := json.Unmarshal(data, &options); err != nil {
+		return nil, err
+	}
+
+	for i := range options {
+		option := &options[i]
+
+		if option.Name == "" || strings.ContainsAny(option.Name, " \t\n") {
+			return nil, fmt.Errorf("cheat name %q must be a single word", option.Name)
+		}
+
+		option.duration, err = time.ParseDuration(option.Duration)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration for cheat %s: %v", option.Name, err)
+		}
+
+		if option.Votes < 1 {
+			option.Votes = 1
+		}
+
+		for _, code := range option.Codes {
+			cheat, err := emulator.ParseCheat(code)
+			if err != nil {
+				return nil, fmt.Errorf("invalid code for cheat %s: %v", option.Name, err)
+			}
+
+			option.cheats = append(option.cheats, cheat)
+		}
+	}
+
+	return options, nil
+}
+
+// Returns the name of the cheat the comment votes for, if it's a vote.
+func parseCheatVote(message string) (string, bool) {
+	fields := strings.Fields(message)
+	if len(fields) != 2 || !strings.EqualFold(fields[0], cheatVotePrefix) {
+		return "", false
+	}
+
+	return fields[1], true
+}
+
+// Caller must hold g.mu.
+func (g *Game) cheatOption(name string) (CheatOption, bool) {
+	for _, option := range g.CheatOptions {
+		if strings.EqualFold(option.Name, name) {
+			return option, true
+		}
+	}
+
+	return CheatOption{}, false
+}
+
+// Records a viewer's vote for a cheat, activating it once enough viewers have
+// voted for it.
+func (g *Game) voteForCheat(userId, name string) {
+	// Facebook leaves out who commented unless the Page has granted access to
+	// it, and votes that can't be told apart would all count as one viewer's.
+	if userId == "" {
+		fmt.Println("Ignoring cheat vote from an unknown viewer")
+		return
+	}
+
+	g.mu.Lock()
+
+	option, ok := g.cheatOption(name)
+	if _, active := g.activeCheats[option.Name]; !ok || active {
+		g.mu.Unlock()
+		return
+	}
+
+	if g.cheatVotes[option.Name] == nil {
+		g.cheatVotes[option.Name] = map[string]time.Time{}
+	}
+	g.cheatVotes[option.Name][userId] = time.Now()
+
+	activated := g.cheatVoteCount(option.Name) >= option.Votes
+	if activated {
+		g.activeCheats[option.Name] = time.Now().Add(option.duration)
+		delete(g.cheatVotes, option.Name)
+	}
+
+	g.mu.Unlock()
+
+	if activated {
+		fmt.Printf("Cheat %s activated for %s\n", option.Name, option.duration)
+	}
+
+	g.updateCheats()
+}
+
+// Caller must hold g.mu.
+func (g *Game) cheatVoteCount(name string) int {
+	cutoff := time.Now().Add(-cheatVoteWindow)
+	count := 0
+
+	for userId, votedAt := range g.cheatVotes[name] {
+		if votedAt.Before(cutoff) {
+			delete(g.cheatVotes[name], userId)
+			continue
+		}
+
+		count++
+	}
+
+	return count
+}
+
+// Turns off cheats as they expire and keeps the emulator and overlay up to
+// date.
+func (g *Game) manageCheats() {
+	ticker := time.NewTicker(time.Second)
+
+	for range ticker.C {
+		g.updateCheats()
+	}
+}
+
+func (g *Game) updateCheats() {
+	g.mu.Lock()
+
+	var cheats []emulator.Cheat
+	var lines []string
+
+	for _, active := range g.sortedActiveCheats() {
+		option, ok := g.cheatOption(active.Name)
+		remaining := time.Until(active.Expires)
+
+		if !ok || remaining <= 0 {
+			delete(g.activeCheats, active.Name)
+			fmt.Println("Cheat expired:", active.Name)
+			continue
+		}
+
+		cheats = append(cheats, option.cheats...)
+		lines = append(lines, fmt.Sprintf("%s %d:%02d", strings.ToUpper(option.Name), int(remaining.Minutes()), int(remaining.Seconds())%60))
+	}
+
+	// Then the cheats being voted for.
+	for _, option := range g.CheatOptions {
+		if _, active := g.activeCheats[option.Name]; active {
+			continue
+		}
+
+		if votes := g.cheatVoteCount(option.Name); votes > 0 {
+			lines = append(lines, fmt.Sprintf("%s %d/%d votes", strings.ToUpper(option.Name), votes, option.Votes))
+		}
+	}
+
+	g.mu.Unlock()
+
+	g.Emulator.SetCheats(cheats)
+
+	if len(lines) > maxOverlayCheats {
+		lines = lines[:maxOverlayCheats]
+	}
+
+	if err := g.Obs.UpdateCheats(lines); err != nil {
+		fmt.Fprintln(os.Stderr, "Error updating cheats on overlay:", err)
+	}
+}
+
+// Returns the active cheats, soonest to expire first. Caller must hold g.mu.
+func (g *Game) sortedActiveCheats() []ActiveCheat {
+	var active []ActiveCheat
+
+	for name, expires := range g.activeCheats {
+		active = append(active, ActiveCheat{Name: name, Expires: expires})
+	}
+
+	sort.Slice(active, func(i, j int) bool {
+		return active[i].Expires.Before(active[j].Expires)
+	})
+
+	return active
+}
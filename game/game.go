@@ -2,9 +2,12 @@ package game
 
 import (
 	"encoding/json"
+	"expvar"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/paked/nes/nes"
@@ -12,19 +15,42 @@ import (
 	"github.com/zachlatta/nostalgic-rewind/emulator"
 	"github.com/zachlatta/nostalgic-rewind/facebook"
 	"github.com/zachlatta/nostalgic-rewind/obs"
+	"github.com/zachlatta/nostalgic-rewind/rom"
 	"github.com/zachlatta/nostalgic-rewind/util"
+	"github.com/zachlatta/nostalgic-rewind/votes"
 )
 
 const (
 	GameSavePath = "game.json"
 
+	nesSaveFileName = "save.dat"
+
 	actionInterval   = 10
 	pollInterval     = 2 * time.Second
 	inactivityCutoff = 1 * time.Minute
 
+	// The poll interval is doubled, up to maxPollInterval, while Facebook reports
+	// that more than highApiUsage percent of our rate limit is used, and halved
+	// back down to pollInterval once usage drops below lowApiUsage.
+	maxPollInterval = 1 * time.Minute
+	highApiUsage    = 75
+	lowApiUsage     = 50
+
+	// Videos with more reactions than this are polled incrementally instead of
+	// downloading every reaction each poll. Only the newest few are fetched
+	// with each poll, and older pages only when the reaction summary changes.
+	incrementalReactionThreshold = 500
+	incrementalReactionPageSize  = 25
+	fullReactionSyncInterval     = 5 * time.Minute
+
 	buttonPressTime = 1 * time.Second
 )
 
+var defaultRetention, _ = ParseRetentionPolicy(DefaultRetentionPolicy)
+
+// Published so the current poll interval can be watched through /debug/vars.
+var pollIntervalVar = expvar.NewFloat("poll_interval_seconds")
+
 var buttonToString = map[int]string{
 	nes.ButtonUp:     "up",
 	nes.ButtonDown:   "down",
@@ -39,27 +65,117 @@ var buttonToString = map[int]string{
 type Game struct {
 	Video       facebook.LiveVideo `json:"-"`
 	RomPath     string
+	RomHash     string
 	SavePath    string
 	AccessToken string
 	Emulator    *emulator.Emulator `json:"-"`
 	Obs         obs.Obs
 
-	startTime time.Time `json: "startTime"`
+	// Patches applied to the ROM at RomPath, in order. Informational only, the
+	// ROM at RomPath has already been patched.
+	Patches []string
+
+	// Cheats viewers can vote for. Must be set before calling Start.
+	CheatOptions []CheatOption `json:"-"`
+
+	// Optional map of the game's memory, whose watches are read every frame.
+	// Must be set before calling Start.
+	MemoryMap *emulator.MemoryMap `json:"-"`
+
+	// Story milestones to look out for, which need MemoryMap, and whether to
+	// comment on the video when one is reached. Must be set before calling
+	// Start.
+	Milestones     []Milestone `json:"-"`
+	PostMilestones bool        `json:"-"`
+
+	// Controls for particular screens, which need MemoryMap. Must be set before
+	// calling Start.
+	ButtonSets []ButtonSet `json:"-"`
+
+	// Lets viewers vote for names by comment. Needs MemoryMap. Must be set
+	// before calling Start.
+	NameEntry *NameEntry `json:"-"`
+
+	// Optional server accepting votes from outside of Facebook. Votes are only
+	// accepted if this is set before calling Start.
+	VoteServer *votes.Server `json:"-"`
+
+	// Optional server receiving comments and reactions from Page webhooks
+	// instead of polling for them. Must be set before calling Start.
+	Webhooks *facebook.WebhookServer `json:"-"`
+
+	// Which snapshots of the saves to keep. Defaults to
+	// DefaultRetentionPolicy.
+	Retention RetentionPolicy `json:"-"`
+
+	startTime time.Time
+
+	// Uptime from previous runs.
+	pastUptime time.Duration
 
 	// Key is user ID
 	reactions         map[string]facebook.Reaction
 	lastUserReactions map[string]time.Time
 
-	buttonsToPress chan int
+	// Key is the user ID given by the external system
+	externalVotes map[string]votes.Vote
+
+	// How long pollers wait between requests to Facebook.
+	pollDelay time.Duration
+
+	// Set once comments can't be streamed and have to be polled for instead.
+	pollComments   bool
+	commentsCursor string
+
+	// Reported by Facebook when polling the video.
+	videoStatus string
+	liveViews   int
+
+	viewers            ViewerSession
+	pastViewerSessions []ViewerSession
+
+	// Total time with at least one active player, across every run.
+	playTime time.Duration
+
+	reachedMilestones []ReachedMilestone
+	stats             RunStats
+
+	// Button set for the screen the game is on. nil for the default controls.
+	screen *ButtonSet
+
+	// Vote viewers are taking part in by comment on the name entry screen. nil
+	// unless one is running.
+	entryVote *entryVote
+
+	// Key is the cheat's name, then the user ID
+	cheatVotes map[string]map[string]time.Time
+	// Key is the cheat's name, value is when it expires
+	activeCheats map[string]time.Time
+
+	// Guards the reaction, vote, polling, and video status fields above, which
+	// are written to by the pollers and read elsewhere.
+	mu sync.Mutex
+
+	// Held while an action's buttons are being pressed, so actions coming from
+	// different goroutines don't interleave.
+	inputMu sync.Mutex
+
+	buttonsToPress chan Action
 
 	comments        chan facebook.Comment
 	lastCommentTime time.Time
 }
 
-func NewFromSave(save Save, vid facebook.LiveVideo, accessToken string) (Game, error) {
-	romPath := save.RomPath
+// Creates a game that picks up where the save left off. The ROM is loaded from
+// romPath rather than the path in the save, since it may have moved since.
+func NewFromSave(save Save, vid facebook.LiveVideo, romPath string, accessToken string) (Game, error) {
 	savePath := save.SavePath
 
+	saveDir, err := RomSaveDir(savePath, romPath)
+	if err != nil {
+		return Game{}, err
+	}
+
 	playerOne := ui.NewKeyboardControllerAdapter()
 	playerTwo := &ui.DummyControllerAdapter{}
 
@@ -67,7 +183,7 @@ func NewFromSave(save Save, vid facebook.LiveVideo, accessToken string) (Game, e
 		emulator.DefaultSettings,
 		playerOne,
 		playerTwo,
-		nesSaveFilePath(savePath, romPath),
+		save.savestatePath,
 	)
 
 	if err != nil {
@@ -76,18 +192,41 @@ func NewFromSave(save Save, vid facebook.LiveVideo, accessToken string) (Game, e
 
 	streamUrl, streamKey := util.SplitStreamUrl(vid.StreamUrl)
 
+	o := obs.New(streamUrl, streamKey)
+	o.RestoreCounters(save.ButtonPresses, save.MostRecentPresses)
+
+	activeCheats := map[string]time.Time{}
+	for _, active := range save.ActiveCheats {
+		activeCheats[active.Name] = active.Expires
+	}
+
 	return Game{
 		Video:       vid,
 		RomPath:     romPath,
+		RomHash:     filepath.Base(saveDir),
 		SavePath:    savePath,
 		AccessToken: accessToken,
 		Emulator:    e,
-		Obs:         obs.New(streamUrl, streamKey),
+		Obs:         o,
+
+		pastUptime: save.Uptime,
+		playTime:   save.PlayTime,
 
 		reactions:         save.PastReactions,
 		lastUserReactions: save.LastUserReactions,
+		externalVotes:     map[string]votes.Vote{},
+		pollDelay:         pollInterval,
+		Retention:         defaultRetention,
+
+		pastViewerSessions: save.ViewerSessions,
+
+		reachedMilestones: save.Milestones,
+		stats:             save.Stats,
 
-		buttonsToPress: make(chan int),
+		cheatVotes:   map[string]map[string]time.Time{},
+		activeCheats: activeCheats,
+
+		buttonsToPress: make(chan Action),
 
 		comments:        make(chan facebook.Comment),
 		lastCommentTime: time.Now(),
@@ -95,14 +234,26 @@ func NewFromSave(save Save, vid facebook.LiveVideo, accessToken string) (Game, e
 }
 
 func New(vid facebook.LiveVideo, romPath string, accessToken string, savePath string) (Game, error) {
+	saveDir, err := RomSaveDir(savePath, romPath)
+	if err != nil {
+		return Game{}, err
+	}
+
 	playerOne := ui.NewKeyboardControllerAdapter()
 	playerTwo := &ui.DummyControllerAdapter{}
 
+	// There's no game save to pair the savestate with, so resume from whichever
+	// one is intact.
+	statePath, err := util.LatestValidFile(filepath.Join(saveDir, nesSaveFileName))
+	if err != nil && !os.IsNotExist(err) {
+		return Game{}, err
+	}
+
 	e, err := emulator.NewEmulator(
 		emulator.DefaultSettings,
 		playerOne,
 		playerTwo,
-		nesSaveFilePath(savePath, romPath),
+		statePath,
 	)
 
 	if err != nil {
@@ -114,6 +265,7 @@ func New(vid facebook.LiveVideo, romPath string, accessToken string, savePath st
 	return Game{
 		Video:       vid,
 		RomPath:     romPath,
+		RomHash:     filepath.Base(saveDir),
 		SavePath:    savePath,
 		AccessToken: accessToken,
 		Emulator:    e,
@@ -121,8 +273,14 @@ func New(vid facebook.LiveVideo, romPath string, accessToken string, savePath st
 
 		reactions:         map[string]facebook.Reaction{},
 		lastUserReactions: map[string]time.Time{},
+		externalVotes:     map[string]votes.Vote{},
+		pollDelay:         pollInterval,
+		Retention:         defaultRetention,
 
-		buttonsToPress: make(chan int),
+		cheatVotes:   map[string]map[string]time.Time{},
+		activeCheats: map[string]time.Time{},
+
+		buttonsToPress: make(chan Action),
 
 		comments:        make(chan facebook.Comment),
 		lastCommentTime: time.Now(),
@@ -135,58 +293,178 @@ func (g *Game) Start() {
 	fmt.Println("Direct your stream to:", g.Video.StreamUrl)
 
 	g.startTime = time.Now()
+	g.viewers = ViewerSession{Start: g.startTime, End: g.startTime}
 
 	go g.startObs()
-	go g.pollForReactions()
+	go g.adaptPollInterval()
+	go g.pollVideo()
+	go g.handleComments()
 	go g.buttonCountdown()
 	go g.handleButtonPresses()
 	go g.continuoslySave()
 
+	if g.VoteServer != nil {
+		go g.listenForVotes()
+	}
+
+	if len(g.CheatOptions) > 0 {
+		go g.manageCheats()
+	}
+
+	if hasParty(g.MemoryMap) {
+		go g.showParty()
+	}
+
+	if g.MemoryMap != nil && len(g.Milestones) > 0 {
+		go g.watchMilestones()
+	}
+
+	if g.MemoryMap != nil {
+		go g.trackStats()
+	}
+
+	if g.MemoryMap != nil && len(g.ButtonSets) > 0 {
+		go g.watchScreen()
+	}
+
+	if g.MemoryMap != nil && g.NameEntry != nil {
+		go g.watchNameEntry()
+	}
+
+	go g.showStats()
+
+	if g.Webhooks != nil {
+		go g.listenForWebhooks()
+	} else {
+		go g.receiveComments()
+	}
+
 	// Emulator must be on main thread
 	g.startEmulator()
 }
 
+// Saves the emulator's state and the game's state. Both are written safely
+// (see util.WriteFileSafely), so a crash midway through leaves the last good
+// save in place.
 func (g *Game) Save() error {
-	epath := nesSaveFilePath(g.SavePath, g.RomPath)
-	err := g.Emulator.SaveState(epath)
+	epath := g.nesSaveFilePath()
+	err := util.WriteFileSafely(epath, g.Emulator.SaveState)
 	if err != nil {
 		return err
 	}
 
+	stateChecksum, err := util.FileChecksum(epath)
+	if err != nil {
+		return err
+	}
+
+	buttonPresses, mostRecentPresses := g.Obs.Counters()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
 	save := Save{
+		Version:           CurrentSaveVersion,
 		PastReactions:     g.reactions,
 		LastUserReactions: g.lastUserReactions,
 		RomPath:           g.RomPath,
+		RomHash:           g.RomHash,
+		Patches:           g.Patches,
 		SavePath:          g.SavePath,
+		ViewerSessions:    append(append([]ViewerSession{}, g.pastViewerSessions...), g.viewers),
+		ButtonPresses:     buttonPresses,
+		MostRecentPresses: mostRecentPresses,
+		Uptime:            g.uptime(),
+		PlayTime:          g.playTime,
+		ActiveCheats:      g.sortedActiveCheats(),
+		Milestones:        g.reachedMilestones,
+		Stats:             g.stats,
+		SavestateChecksum: stateChecksum,
 	}
 
-	fpath := filepath.Join(g.SavePath, util.MD5HashString(g.RomPath), GameSavePath)
+	data, err := json.Marshal(save)
+	if err != nil {
+		return err
+	}
 
-	var f *os.File
+	fpath := filepath.Join(g.saveDir(), GameSavePath)
 
-	// Check if file doesn't exist
-	if ok, err := util.FileExists(fpath); !ok || err != nil {
-		f, err = os.Create(fpath)
-	} else {
-		f, err = os.OpenFile(fpath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0777)
-	}
+	return util.WriteDataSafely(fpath, data)
+}
+
+// Loads the game save at the given path, along with the savestate saved with
+// it. If either of the latest pair is corrupt, both fall back to the previous
+// generation, so one is never resumed alongside the other's previous
+// generation. The returned error satisfies os.IsNotExist if there's no save.
+func LoadSave(path string) (Save, error) {
+	var save Save
 
+	validPaths, err := util.ValidGenerations(path)
 	if err != nil {
-		return err
+		return save, err
 	}
 
-	err = json.NewEncoder(f).Encode(save)
+	statePath := filepath.Join(filepath.Dir(path), nesSaveFileName)
+
+	for _, validPath := range validPaths {
+		data, err := ioutil.ReadFile(validPath)
+		if err != nil {
+			return save, err
+		}
+
+		save, err = DecodeSave(data)
+		if err != nil {
+			return save, err
+		}
 
-	return err
+		var paired bool
+		save.savestatePath, paired, err = pairedSavestate(statePath, save.SavestateChecksum)
+		if err != nil {
+			return save, err
+		}
+
+		if !paired {
+			fmt.Fprintln(os.Stderr, "Savestate saved with", validPath, "is missing or corrupt")
+			continue
+		}
+
+		if validPath != path {
+			fmt.Println("Latest save is corrupt. Falling back to:", validPath)
+		}
+
+		return save, nil
+	}
+
+	return save, fmt.Errorf("no copy of %s has its savestate", path)
 }
 
-func (g *Game) Load() error {
-	err := g.Emulator.LoadState(g.SavePath)
-	if err != nil {
-		return err
+// Returns the generation of the savestate at path with the given checksum, or
+// an empty path if there are no savestates at all (such as when one's been
+// removed on purpose). Saves from before savestates were checksummed resume
+// from whichever one is intact. ok is false if there are savestates, but none
+// that go with the save.
+func pairedSavestate(path, checksum string) (paired string, ok bool, err error) {
+	if checksum == "" {
+		paired, err = util.LatestValidFile(path)
+		if os.IsNotExist(err) {
+			return "", true, nil
+		}
+
+		return paired, err == nil, err
+	}
+
+	paired, ok, err = util.GenerationWithChecksum(path, checksum)
+	if err != nil || ok {
+		return paired, ok, err
+	}
+
+	for _, candidate := range []string{path, path + util.PreviousExtension} {
+		if exists, err := util.FileExists(candidate); err != nil || exists {
+			return "", false, err
+		}
 	}
 
-	return nil
+	return "", true, nil
 }
 
 func (g *Game) startObs() {
@@ -196,41 +474,400 @@ func (g *Game) startObs() {
 	}
 }
 
-func (g *Game) pollForReactions() {
+// Stretches the poll interval as we approach Facebook's rate limits and
+// shrinks it again when there's headroom.
+func (g *Game) adaptPollInterval() {
 	ticker := time.NewTicker(pollInterval)
 
 	for range ticker.C {
-		reactions, err := facebook.Reactions(g.Video.Id, g.AccessToken)
+		usage := facebook.MaxUsage()
+
+		g.mu.Lock()
+		delay := g.pollDelay
+
+		if usage >= highApiUsage && delay < maxPollInterval {
+			delay *= 2
+			if delay > maxPollInterval {
+				delay = maxPollInterval
+			}
+
+			fmt.Printf("Facebook API usage at %d%%. Slowing polling to every %s.\n", usage, delay)
+		} else if usage < lowApiUsage && delay > pollInterval {
+			delay /= 2
+			if delay < pollInterval {
+				delay = pollInterval
+			}
+
+			fmt.Printf("Facebook API usage at %d%%. Speeding polling up to every %s.\n", usage, delay)
+		}
+
+		g.pollDelay = delay
+		g.mu.Unlock()
+
+		pollIntervalVar.Set(delay.Seconds())
+	}
+}
+
+func (g *Game) currentPollInterval() time.Duration {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.pollDelay
+}
+
+// Polls Facebook for the video's status, reactions, and (if they can't be
+// streamed) comments, all in a single batch request.
+func (g *Game) pollVideo() {
+	var lastFullSync time.Time
+	forceFullSync := true
+
+	// Reaction summary from the last successful sync
+	var lastSummary map[facebook.ReactionType]int
+
+	for {
+		time.Sleep(g.currentPollInterval())
+
+		fullSync := forceFullSync || time.Since(lastFullSync) > fullReactionSyncInterval
+
+		g.mu.Lock()
+		opts := facebook.PollOptions{
+			SkipReactions:  g.Webhooks != nil,
+			Comments:       g.pollComments,
+			CommentsCursor: g.commentsCursor,
+		}
+		g.mu.Unlock()
+
+		if !fullSync && reactionTotal(lastSummary) > incrementalReactionThreshold {
+			opts.ReactionLimit = incrementalReactionPageSize
+		}
+
+		poll, err := facebook.PollVideo(g.Video.Id, g.AccessToken, opts)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, "Error polling for reactions:", err)
+			fmt.Fprintln(os.Stderr, "Error polling video:", err)
 			os.Exit(1)
 		}
 
-		// Update g.lastUserReactions
-		for _, reaction := range reactions {
-			lastReaction := g.reactions[reaction.AuthorId]
+		// Each part of the poll can fail on its own. Skip whatever failed this
+		// time around and try it again on the next poll.
+		if poll.VideoErr != nil {
+			fmt.Fprintln(os.Stderr, "Error polling video status:", poll.VideoErr)
+		} else {
+			g.updateVideoStatus(poll)
+		}
 
-			if lastReaction != reaction {
-				g.lastUserReactions[reaction.AuthorId] = time.Now()
+		switch {
+		case opts.SkipReactions:
+			// Reactions are coming in through webhooks.
+		case poll.ReactionsErr != nil:
+			fmt.Fprintln(os.Stderr, "Error polling for reactions:", poll.ReactionsErr)
+		case poll.VideoErr != nil:
+			// Reactions can't be synced without the summary.
+		default:
+			summaryChanged := !sameReactionSummary(poll.ReactionSummary, lastSummary)
+
+			needsFullSync, err := g.syncReactions(poll, fullSync, summaryChanged)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Error polling for reactions:", err)
+				needsFullSync = true
+			} else {
+				lastSummary = poll.ReactionSummary
+
+				if fullSync {
+					lastFullSync = time.Now()
+				}
 			}
+
+			forceFullSync = needsFullSync
+
+			g.updateVoteBreakdown()
+		}
+
+		if opts.Comments {
+			if poll.CommentsErr != nil {
+				fmt.Fprintln(os.Stderr, "Error polling for comments:", poll.CommentsErr)
+			} else {
+				g.mu.Lock()
+				g.commentsCursor = poll.CommentsCursor
+				g.mu.Unlock()
+
+				for _, comment := range poll.Comments {
+					g.comments <- comment
+				}
+			}
+		}
+	}
+}
+
+func (g *Game) updateVideoStatus(poll facebook.VideoPoll) {
+	g.mu.Lock()
+
+	if poll.Status != g.videoStatus {
+		fmt.Println("Video status:", poll.Status)
+	}
+
+	g.videoStatus = poll.Status
+	g.liveViews = poll.LiveViews
+	g.viewers.record(poll.LiveViews, time.Now())
+
+	g.mu.Unlock()
+
+	if err := g.Obs.UpdateViewers(poll.LiveViews); err != nil {
+		fmt.Fprintln(os.Stderr, "Error updating viewer count:", err)
+	}
+}
+
+// Brings the known reactions up to date with the poll. Small audiences (or
+// full syncs) are downloaded in full. Big ones only have their newest
+// reactions checked, with an occasional full sync to catch anyone who took
+// their reaction back. Returns whether a full sync is needed next time.
+func (g *Game) syncReactions(poll facebook.VideoPoll, fullSync, summaryChanged bool) (needsFullSync bool, err error) {
+	total := reactionTotal(poll.ReactionSummary)
+
+	if fullSync || total <= incrementalReactionThreshold {
+		reactions := poll.Reactions
+
+		if poll.MoreReactions {
+			reactions, err = facebook.Reactions(g.Video.Id, g.AccessToken)
+			if err != nil {
+				return true, err
+			}
+		}
+
+		g.replaceReactions(reactions)
+
+		return false, nil
+	}
+
+	known := g.knownReactions()
+
+	var changed []facebook.Reaction
+	for _, reaction := range poll.Reactions {
+		if known[reaction.AuthorId] != reaction {
+			changed = append(changed, reaction)
 		}
+	}
 
-		// Update g.reactions
-		reactionMap := map[string]facebook.Reaction{}
-		for _, reaction := range reactions {
-			reactionMap[reaction.AuthorId] = reaction
+	// If everything on the newest page changed, there are probably more changes
+	// on the pages after it. Those are only fetched when the summary changed, so
+	// polls that change nothing stay a single small page.
+	if summaryChanged && poll.MoreReactions && len(changed) == len(poll.Reactions) {
+		changed, err = facebook.ChangedReactions(g.Video.Id, g.AccessToken, known)
+		if err != nil {
+			return true, err
 		}
-		g.reactions = reactionMap
+	}
+
+	// If the totals don't line up, someone removed their reaction.
+	return g.updateReactions(changed) != total, nil
+}
+
+func reactionTotal(summary map[facebook.ReactionType]int) int {
+	total := 0
+	for _, count := range summary {
+		total += count
+	}
 
-		// Update vote breakdown
-		buttonVoteMap := map[int]int{}
-		for reactionType, count := range g.reactionCounts(true) {
-			buttonVoteMap[reactionToButton(reactionType)] = count
+	return total
+}
+
+func sameReactionSummary(a, b map[facebook.ReactionType]int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for reactionType, count := range a {
+		if b[reactionType] != count {
+			return false
 		}
-		if err := g.Obs.UpdateVoteBreakdown(buttonVoteMap); err != nil {
-			fmt.Fprintln(os.Stderr, "Error updating vote breakdown:", err)
+	}
+
+	return true
+}
+
+func (g *Game) removeReaction(userId string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	delete(g.reactions, userId)
+}
+
+// Returns a copy of the known reactions that's safe to use without holding
+// g.mu.
+func (g *Game) knownReactions() map[string]facebook.Reaction {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	known := make(map[string]facebook.Reaction, len(g.reactions))
+	for userId, reaction := range g.reactions {
+		known[userId] = reaction
+	}
+
+	return known
+}
+
+// Replaces every known reaction with the given ones.
+func (g *Game) replaceReactions(reactions []facebook.Reaction) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	// Update g.lastUserReactions
+	for _, reaction := range reactions {
+		lastReaction := g.reactions[reaction.AuthorId]
+
+		if lastReaction != reaction {
+			g.lastUserReactions[reaction.AuthorId] = time.Now()
+		}
+	}
+
+	// Update g.reactions
+	reactionMap := map[string]facebook.Reaction{}
+	for _, reaction := range reactions {
+		reactionMap[reaction.AuthorId] = reaction
+	}
+	g.reactions = reactionMap
+}
+
+// Records the given new or changed reactions and returns how many of the known
+// reactions are of a type counted in the reaction summary.
+func (g *Game) updateReactions(changed []facebook.Reaction) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, reaction := range changed {
+		g.reactions[reaction.AuthorId] = reaction
+		g.lastUserReactions[reaction.AuthorId] = time.Now()
+	}
+
+	summarized := 0
+	for _, reaction := range g.reactions {
+		if reaction.Type.Summarized() {
+			summarized += 1
+		}
+	}
+
+	return summarized
+}
+
+// Streams comments from Facebook as they're made, falling back to having
+// pollVideo poll for new ones if the video doesn't support streaming.
+func (g *Game) receiveComments() {
+	for {
+		err := facebook.StreamComments(g.Video.Id, g.AccessToken, g.comments)
+		if err == facebook.ErrStreamingUnavailable {
+			break
+		}
+
+		fmt.Fprintln(os.Stderr, "Comment stream closed, reconnecting:", err)
+		time.Sleep(g.currentPollInterval())
+	}
+
+	fmt.Println("Live comment streaming unavailable. Polling for comments instead.")
+
+	// Skip past comments made before we started so only new ones are handled.
+	var cursor string
+	for {
+		var err error
+		cursor, err = facebook.LatestCommentsCursor(g.Video.Id, g.AccessToken)
+		if err == nil {
+			break
+		}
+
+		fmt.Fprintln(os.Stderr, "Error finding the latest comment, retrying:", err)
+		time.Sleep(g.currentPollInterval())
+	}
+
+	g.mu.Lock()
+	g.pollComments = true
+	g.commentsCursor = cursor
+	g.mu.Unlock()
+}
+
+func (g *Game) handleComments() {
+	for comment := range g.comments {
+		fmt.Printf("Comment from %s: %s\n", comment.AuthorName, comment.Message)
+
+		if name, ok := parseCheatVote(comment.Message); ok {
+			g.voteForCheat(comment.AuthorId, name)
+		}
+
+		if g.NameEntry != nil {
+			g.voteForEntry(comment.AuthorId, comment.Message)
+		}
+
+		g.lastCommentTime = comment.Created
+	}
+}
+
+func (g *Game) listenForWebhooks() {
+	go func() {
+		if err := g.Webhooks.ListenAndServe(); err != nil {
+			fmt.Fprintln(os.Stderr, "Error running webhook server:", err)
 			os.Exit(1)
 		}
+	}()
+
+	fmt.Println("Receiving webhooks on", g.Webhooks.Addr)
+
+	// Pages used for streaming only have the one live video on them, so events
+	// aren't filtered by post.
+	for event := range g.Webhooks.Events {
+		switch {
+		case event.Comment != nil:
+			if event.Verb == facebook.WebhookVerbAdd {
+				g.comments <- *event.Comment
+			}
+		case event.Reaction != nil:
+			if event.Verb == facebook.WebhookVerbRemove {
+				g.removeReaction(event.Reaction.AuthorId)
+			} else {
+				g.updateReactions([]facebook.Reaction{*event.Reaction})
+			}
+
+			g.updateVoteBreakdown()
+		}
+	}
+}
+
+func (g *Game) listenForVotes() {
+	go func() {
+		if err := g.VoteServer.ListenAndServe(); err != nil {
+			fmt.Fprintln(os.Stderr, "Error running vote server:", err)
+			os.Exit(1)
+		}
+	}()
+
+	fmt.Println("Accepting votes on", g.VoteServer.Addr)
+
+	for vote := range g.VoteServer.Votes {
+		g.mu.Lock()
+		g.externalVotes[vote.UserId] = vote
+		g.mu.Unlock()
+
+		g.updateVoteBreakdown()
+	}
+}
+
+func (g *Game) updateVoteBreakdown() {
+	g.mu.Lock()
+	counts, actions := g.actionCounts(true)
+	screen := g.screen
+	var breakdown []obs.ActionVotes
+	if screen != nil {
+		breakdown = g.screenVoteBreakdown(counts)
+	}
+	g.mu.Unlock()
+
+	var err error
+	if screen != nil {
+		err = g.Obs.UpdateScreenVoteBreakdown(screen.Name, breakdown)
+	} else {
+		err = g.Obs.UpdateVoteBreakdown(buttonCounts(counts, actions))
+	}
+
+	// Votes can come in before OBS is ready, and the next one will bring the
+	// overlay up to date anyway.
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error updating vote breakdown:", err)
 	}
 }
 
@@ -240,55 +877,122 @@ func (g *Game) buttonCountdown() {
 
 	for range ticker.C {
 		g.Obs.UpdateNextButtonPress(timer)
-		g.Obs.UpdateTotalUptime(g.startTime, time.Now())
-		g.Obs.UpdateActivePlayers(len(g.activePlayers()))
+		g.Obs.UpdateTotalUptime(g.uptime())
+
+		g.mu.Lock()
+		activePlayers := len(g.activePlayers())
+		counts, actions := g.actionCounts(true)
+		naming := g.entryVote != nil
+		if activePlayers > 0 {
+			g.playTime += time.Second
+		}
+		g.mu.Unlock()
+
+		g.Obs.UpdateActivePlayers(activePlayers)
 
 		timer -= 1
 
 		if timer == 0 {
 			timer = actionInterval
 
-			mostCommonReact := mostCommonReact(g.reactionCounts(true))
-			if mostCommonReact == -1 {
-				fmt.Println("No reactions. Skipping button press.")
+			if naming {
+				fmt.Println("Name entry vote in progress. Skipping button press.")
+				continue
+			}
+
+			mostCommonAction := mostCommonAction(counts)
+			if mostCommonAction == "" {
+				fmt.Println("No votes. Skipping button press.")
 				continue
 			}
 
-			g.buttonsToPress <- reactionToButton(mostCommonReact)
+			// Macros can take a while. Rather than holding up the countdown, the
+			// vote is dropped if the last action is still running.
+			select {
+			case g.buttonsToPress <- actions[mostCommonAction]:
+			default:
+				fmt.Println("Still running the last action. Skipping button press.")
+			}
 		}
 	}
 }
 
-func (g Game) activePlayers() map[string]struct{} {
+// Total time the game has been streaming, across every run.
+func (g *Game) uptime() time.Duration {
+	return g.pastUptime + time.Since(g.startTime)
+}
+
+// Caller must hold g.mu.
+func (g *Game) activePlayers() map[string]struct{} {
 	activeIds := map[string]struct{}{}
 
-	for userId, lastReactionTime := range g.lastUserReactions {
-		// cutoff = current time - inactivity cutoff
-		cutoff := time.Now().Add(-inactivityCutoff)
+	// cutoff = current time - inactivity cutoff
+	cutoff := time.Now().Add(-inactivityCutoff)
 
+	for userId, lastReactionTime := range g.lastUserReactions {
 		if lastReactionTime.After(cutoff) {
 			activeIds[userId] = struct{}{}
 		}
 	}
 
+	for userId, vote := range g.externalVotes {
+		if vote.Cast.After(cutoff) {
+			activeIds[externalPlayerId(userId)] = struct{}{}
+		}
+	}
+
 	return activeIds
 }
 
-func (g Game) reactionCounts(onlyIncludeActive bool) map[facebook.ReactionType]int {
-	countMap := map[facebook.ReactionType]int{}
+// Returns the number of votes for each action on the current screen, combining
+// Facebook reactions with votes from the vote server, and the actions
+// themselves. Both are keyed by the action's name. External votes are always
+// for a single button. Caller must hold g.mu.
+func (g *Game) actionCounts(onlyIncludeActive bool) (map[string]int, map[string]Action) {
+	countMap := map[string]int{}
+	actions := map[string]Action{}
 	activeUserIds := g.activePlayers()
 
 	for userId, reaction := range g.reactions {
-		if onlyIncludeActive {
-			if _, present := activeUserIds[userId]; present == true {
-				countMap[reaction.Type] += 1
-			}
-		} else {
-			countMap[reaction.Type] += 1
+		if _, present := activeUserIds[userId]; onlyIncludeActive && !present {
+			continue
+		}
+
+		if action, ok := g.reactionAction(reaction.Type); ok {
+			countMap[action.Name] += 1
+			actions[action.Name] = action
+		}
+	}
+
+	for userId, vote := range g.externalVotes {
+		if _, present := activeUserIds[externalPlayerId(userId)]; onlyIncludeActive && !present {
+			continue
 		}
+
+		action := buttonAction(vote.Button)
+		countMap[action.Name] += 1
+		actions[action.Name] = action
 	}
 
-	return countMap
+	return countMap, actions
+}
+
+// Returns the votes for single button actions, keyed by button.
+func buttonCounts(counts map[string]int, actions map[string]Action) map[int]int {
+	buttonCounts := map[int]int{}
+
+	for name, count := range counts {
+		if action := actions[name]; len(action.Buttons) == 1 {
+			buttonCounts[action.Buttons[0]] += count
+		}
+	}
+
+	return buttonCounts
+}
+
+// External user IDs are namespaced so they can't collide with Facebook's.
+func externalPlayerId(userId string) string {
+	return "votes:" + userId
 }
 
 func reactionToButton(reaction facebook.ReactionType) int {
@@ -310,60 +1014,230 @@ func reactionToButton(reaction facebook.ReactionType) int {
 	}
 }
 
-// Returns the most common reaction given an array of them
-func mostCommonReact(countMap map[facebook.ReactionType]int) facebook.ReactionType {
-	var foundMostCommon bool
-	var mostCommon facebook.ReactionType
+// Returns the name of the action with the most votes, or an empty string if
+// there are none.
+func mostCommonAction(countMap map[string]int) string {
+	var mostCommon string
 	var maxCount int
 
-	for reactionType, occurances := range countMap {
+	for action, occurances := range countMap {
 		if occurances > maxCount {
-			foundMostCommon = true
-			mostCommon = reactionType
+			mostCommon = action
 			maxCount = occurances
 		}
 	}
 
-	if !foundMostCommon {
-		return -1
-	}
-
 	return mostCommon
 }
 
 func (g *Game) handleButtonPresses() {
 	for action := range g.buttonsToPress {
 		g.Obs.IncrementButtonPresses()
-		g.Obs.AddMostRecentPress(buttonToString[action])
+		g.Obs.AddMostRecentPress(action.Name)
+
+		g.inputMu.Lock()
 
-		g.Emulator.PlayerOneController.Trigger(action, true)
-		time.Sleep(time.Second / 5)
-		g.Emulator.PlayerOneController.Trigger(action, false)
+		if action.Macro != nil {
+			if err := g.runMacro(action.Macro); err != nil {
+				fmt.Printf("Macro %s failed: %v\n", action.Macro.Name, err)
+			}
+		} else {
+			for _, button := range action.Buttons {
+				g.pressButton(button)
+			}
+		}
+
+		g.inputMu.Unlock()
 	}
 }
 
+func (g *Game) pressButton(button int) {
+	g.mu.Lock()
+	g.stats.countInput(buttonToString[button])
+	g.mu.Unlock()
+
+	g.Emulator.PlayerOneController.Trigger(button, true)
+	time.Sleep(time.Second / 5)
+	g.Emulator.PlayerOneController.Trigger(button, false)
+
+	// Give the game a moment to notice the release before the next press.
+	time.Sleep(time.Second / 10)
+}
+
 func (g *Game) startEmulator() {
-	g.Emulator.Play(g.RomPath)
+	sram := newSRAMWriter(filepath.Join(g.saveDir(), sramFileName))
+	go sram.run()
+
+	g.Emulator.OnConsoleReady = g.seedSRAM
+	g.Emulator.OnFrame = sram.frame
+
+	if g.MemoryMap != nil {
+		g.Emulator.SetWatches(*g.MemoryMap)
+
+		expvar.Publish("ram_watch", expvar.Func(func() interface{} {
+			return g.Emulator.Watches().Values()
+		}))
+	}
+
+	// Keep whatever progress was made since the last save when the window is
+	// closed.
+	g.Emulator.OnStop = func(console *nes.Console) {
+		if err := sram.flush(console); err != nil {
+			fmt.Fprintln(os.Stderr, "Error saving SRAM:", err)
+		}
+
+		if err := g.Save(); err != nil {
+			fmt.Fprintln(os.Stderr, "Error saving game:", err)
+		}
+	}
+
+	if err := g.Emulator.Play(g.RomPath); err != nil {
+		fmt.Fprintln(os.Stderr, "Error running emulator:", err)
+		os.Exit(1)
+	}
 }
 
 func (g *Game) continuoslySave() {
 	c := time.Tick(1 * time.Minute)
 	for range c {
-		path := nesSaveFilePath(g.SavePath, g.RomPath)
+		path := g.nesSaveFilePath()
 
 		fmt.Println("Saving NES's game state to:", path)
-		g.Save()
+		if err := g.Save(); err != nil {
+			fmt.Fprintln(os.Stderr, "Error saving game:", err)
+			continue
+		}
 		fmt.Println("Finished saving...")
+
+		if _, err := TakeSnapshot(g.saveDir(), time.Now()); err != nil {
+			fmt.Fprintln(os.Stderr, "Error taking save snapshot:", err)
+			continue
+		}
+
+		if err := PruneSnapshots(g.saveDir(), g.Retention, time.Now()); err != nil {
+			fmt.Fprintln(os.Stderr, "Error pruning save snapshots:", err)
+		}
 	}
 }
 
-func nesSaveFilePath(savePath, romPath string) string {
-	return filepath.Join(savePath, util.MD5HashString(romPath), "save.dat")
+// Returns the directory that saves for the ROM at romPath are kept in, which
+// is named after a hash of the ROM's contents so saves follow the ROM if it's
+// moved. Saves from before then, which were kept in a directory named after a
+// hash of the ROM's path, are moved over.
+func RomSaveDir(savePath, romPath string) (string, error) {
+	romHash, err := rom.HashFile(romPath)
+	if err != nil {
+		return "", err
+	}
+
+	dir := RomHashSaveDir(savePath, romHash)
+	legacyDir := legacyRomSaveDir(savePath, romPath)
+
+	legacyExists, err := util.FileExists(legacyDir)
+	if err != nil {
+		return "", err
+	}
+
+	exists, err := util.FileExists(dir)
+	if err != nil {
+		return "", err
+	}
+
+	if legacyExists && !exists {
+		fmt.Println("Moving saves keyed by ROM path to:", dir)
+
+		if err := os.Rename(legacyDir, dir); err != nil {
+			return "", err
+		}
+	}
+
+	return dir, nil
+}
+
+// Like RomSaveDir, but leaves saves keyed by ROM path where they are, returning
+// their directory if there aren't any keyed by the ROM's hash yet.
+func FindRomSaveDir(savePath, romPath string) (string, error) {
+	romHash, err := rom.HashFile(romPath)
+	if err != nil {
+		return "", err
+	}
+
+	dir := RomHashSaveDir(savePath, romHash)
+
+	exists, err := util.FileExists(dir)
+	if err != nil || exists {
+		return dir, err
+	}
+
+	legacyDir := legacyRomSaveDir(savePath, romPath)
+
+	legacyExists, err := util.FileExists(legacyDir)
+	if err != nil {
+		return "", err
+	}
+
+	if legacyExists {
+		return legacyDir, nil
+	}
+
+	return dir, nil
+}
+
+// Returns the directory that saves for the ROM with the given hash (see
+// rom.Hash) are kept in.
+func RomHashSaveDir(savePath, romHash string) string {
+	return filepath.Join(savePath, romHash)
+}
+
+// Saves used to be keyed by the MD5 of the ROM's path.
+func legacyRomSaveDir(savePath, romPath string) string {
+	return filepath.Join(savePath, util.MD5HashString(romPath))
+}
+
+// Returns the directory patched ROMs are written to, which is kept with the
+// saves so patched ROMs always have the same path.
+func PatchedRomDir(savePath string) string {
+	return filepath.Join(savePath, "patched")
+}
+
+func (g *Game) saveDir() string {
+	return RomHashSaveDir(g.SavePath, g.RomHash)
+}
+
+func (g *Game) nesSaveFilePath() string {
+	return filepath.Join(g.saveDir(), nesSaveFileName)
 }
 
 type Save struct {
+	// Schema version. See CurrentSaveVersion.
+	Version int `json:"version"`
+
 	PastReactions     map[string]facebook.Reaction `json:"past_reactions"`
 	LastUserReactions map[string]time.Time         `json:"last_user_reactions"`
 	RomPath           string                       `json:"rom_path"`
+	RomHash           string                       `json:"rom_hash"`
+	Patches           []string                     `json:"patches"`
 	SavePath          string                       `json:"save_path"`
+
+	// One entry for every time the stream has been started, oldest first.
+	ViewerSessions []ViewerSession `json:"viewer_sessions"`
+
+	// Lifetime counters shown on the overlay.
+	ButtonPresses     int           `json:"button_presses"`
+	MostRecentPresses []string      `json:"most_recent_presses"`
+	Uptime            time.Duration `json:"uptime"`
+	PlayTime          time.Duration `json:"play_time"`
+
+	ActiveCheats []ActiveCheat `json:"active_cheats"`
+
+	// In the order they were reached.
+	Milestones []ReachedMilestone `json:"milestones"`
+
+	Stats RunStats `json:"stats"`
+
+	// SHA-256 of the savestate written along with this save. See LoadSave.
+	SavestateChecksum string `json:"savestate_checksum"`
+
+	// Savestate to resume from, as found by LoadSave. Empty if there isn't one.
+	savestatePath string
 }
@@ -0,0 +1,248 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zachlatta/nostalgic-rewind/util"
+)
+
+const (
+	historyDir = "history"
+
+	// Layout of snapshot directory names. Always in UTC.
+	SnapshotTimeLayout = "20060102T150405Z"
+
+	// Every minute for an hour, hourly for a week, and daily forever.
+	DefaultRetentionPolicy = "1m:1h,1h:168h,24h"
+)
+
+// Files copied into every snapshot.
+var snapshotFiles = []string{nesSaveFileName, GameSavePath}
+
+// Files copied into snapshots when they exist, like the battery save, which
+// only games with one have.
+var optionalSnapshotFiles = []string{sramFileName}
+
+// A timestamped copy of a game's saves, kept under the ROM's save directory.
+type Snapshot struct {
+	Time time.Time
+	Path string
+}
+
+// How long snapshots are kept for. Tiers are checked in order, and the first
+// one covering a snapshot's age decides whether it's kept: only the oldest
+// snapshot in each Every long window survives. Snapshots older than every
+// tier are deleted.
+type RetentionPolicy []RetentionTier
+
+type RetentionTier struct {
+	Every time.Duration
+	// Zero means forever.
+	For time.Duration
+}
+
+// Parses a policy like "1m:1h,1h:168h,24h" (every minute for an hour, hourly
+// for a week, daily forever).
+func ParseRetentionPolicy(policy string) (RetentionPolicy, error) {
+	var tiers RetentionPolicy
+
+	for _, rawTier := range strings.Split(policy, ",") {
+		parts := strings.Split(strings.TrimSpace(rawTier), ":")
+		if len(parts) > 2 {
+			return nil, fmt.Errorf("invalid retention tier %q", rawTier)
+		}
+
+		every, err := time.ParseDuration(parts[0])
+		if err != nil {
+			return nil, err
+		}
+		if every <= 0 {
+			return nil, fmt.Errorf("retention tier %q must keep snapshots at a positive interval", rawTier)
+		}
+
+		tier := RetentionTier{Every: every}
+
+		if len(parts) == 2 {
+			tier.For, err = time.ParseDuration(parts[1])
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		tiers = append(tiers, tier)
+	}
+
+	return tiers, nil
+}
+
+// Copies the current saves in the given ROM save directory into a new
+// snapshot.
+func TakeSnapshot(romSaveDir string, at time.Time) (Snapshot, error) {
+	snapshot := Snapshot{
+		Time: at.UTC().Truncate(time.Second),
+	}
+	snapshot.Path = filepath.Join(romSaveDir, historyDir, snapshot.Time.Format(SnapshotTimeLayout))
+
+	names, err := filesToSnapshot(romSaveDir)
+	if err != nil {
+		return snapshot, err
+	}
+
+	for _, name := range names {
+		data, err := ioutil.ReadFile(filepath.Join(romSaveDir, name))
+		if err != nil {
+			return snapshot, err
+		}
+
+		if err := util.WriteDataSafely(filepath.Join(snapshot.Path, name), data); err != nil {
+			return snapshot, err
+		}
+	}
+
+	return snapshot, nil
+}
+
+// Returns the snapshots in the given ROM save directory, newest first.
+func ListSnapshots(romSaveDir string) ([]Snapshot, error) {
+	dir := filepath.Join(romSaveDir, historyDir)
+
+	entries, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var snapshots []Snapshot
+
+	for _, entry := range entries {
+		t, err := time.Parse(SnapshotTimeLayout, entry.Name())
+		if !entry.IsDir() || err != nil {
+			continue
+		}
+
+		snapshots = append(snapshots, Snapshot{
+			Time: t,
+			Path: filepath.Join(dir, entry.Name()),
+		})
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Time.After(snapshots[j].Time)
+	})
+
+	return snapshots, nil
+}
+
+// Deletes the snapshots in the given ROM save directory that the policy
+// doesn't keep.
+func PruneSnapshots(romSaveDir string, policy RetentionPolicy, now time.Time) error {
+	snapshots, err := ListSnapshots(romSaveDir)
+	if err != nil {
+		return err
+	}
+
+	// Oldest first, so the oldest snapshot in each window is the one kept.
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Time.Before(snapshots[j].Time)
+	})
+
+	type window struct {
+		tier  int
+		start time.Time
+	}
+	kept := map[window]bool{}
+
+	for _, snapshot := range snapshots {
+		age := now.Sub(snapshot.Time)
+		keep := false
+
+		for i, tier := range policy {
+			if tier.For != 0 && age > tier.For {
+				continue
+			}
+
+			w := window{tier: i, start: snapshot.Time.Truncate(tier.Every)}
+			if !kept[w] {
+				kept[w] = true
+				keep = true
+			}
+
+			break
+		}
+
+		if !keep {
+			if err := os.RemoveAll(snapshot.Path); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Replaces the current saves in the given ROM save directory with the ones in
+// the snapshot taken at the given time. The replaced saves are kept as the
+// previous generation, so a restore can be undone.
+func RestoreSnapshot(romSaveDir string, at time.Time) error {
+	snapshots, err := ListSnapshots(romSaveDir)
+	if err != nil {
+		return err
+	}
+
+	for _, snapshot := range snapshots {
+		if !snapshot.Time.Equal(at) {
+			continue
+		}
+
+		names, err := filesToSnapshot(snapshot.Path)
+		if err != nil {
+			return err
+		}
+
+		for _, name := range names {
+			path, err := util.LatestValidFile(filepath.Join(snapshot.Path, name))
+			if err != nil {
+				return err
+			}
+
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				return err
+			}
+
+			if err := util.WriteDataSafely(filepath.Join(romSaveDir, name), data); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return errors.New("no snapshot taken at " + at.UTC().Format(SnapshotTimeLayout))
+}
+
+// Returns the names of the files in dir that belong in a snapshot.
+func filesToSnapshot(dir string) ([]string, error) {
+	names := append([]string{}, snapshotFiles...)
+
+	for _, name := range optionalSnapshotFiles {
+		exists, err := util.FileExists(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+
+		if exists {
+			names = append(names, name)
+		}
+	}
+
+	return names, nil
+}
@@ -0,0 +1,175 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/zachlatta/nostalgic-rewind/emulator"
+	"github.com/zachlatta/nostalgic-rewind/votes"
+)
+
+const (
+	defaultMacroStepPresses = 10
+	defaultMacroTimeout     = 2 * time.Second
+)
+
+// A scripted input sequence viewers can vote for through a ButtonSet, driven by
+// watches in the memory map. Loaded from a JSON list like:
+//
+//	[{
+//		"name": "open_item_menu",
+//		"steps": [
+//			{"press": "b", "until": {"watch": "menu", "values": [0]}, "max": 5},
+//			{"press": "start", "until": {"watch": "menu", "values": [1]}},
+//			{"press": "down", "until": {"watch": "menu_cursor", "values": [1]}, "max": 4},
+//			{"press": "a"}
+//		],
+//		"expect": {"watch": "menu", "values": [2]},
+//		"timeout": "3s"
+//	}]
+//
+// A step with until keeps pressing its button (at most max times) until the
+// condition holds, and is skipped if it already does. A step without a button
+// waits for the condition instead. Once every step has run, the macro waits up
+// to timeout for expect to hold. A macro stops at the first step that fails.
+type Macro struct {
+	Name    string      `json:"name"`
+	Steps   []MacroStep `json:"steps"`
+	Expect  *Condition  `json:"expect"`
+	Timeout string      `json:"timeout"`
+
+	timeout time.Duration
+}
+
+type MacroStep struct {
+	Press string     `json:"press"`
+	Until *Condition `json:"until"`
+	Max   int        `json:"max"`
+
+	button int
+}
+
+// Loads a JSON list of Macros, checking that the memory map has the watches
+// they need.
+func LoadMacros(path string, m emulator.MemoryMap) ([]Macro, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var macros []Macro
+	if err := json.Unmarshal(data, &macros); err != nil {
+		return nil, err
+	}
+
+	for i := range macros {
+		macro := &macros[i]
+
+		if macro.Name == "" || len(macro.Steps) == 0 {
+			return nil, errors.New("macros need a name and steps")
+		}
+
+		macro.timeout = defaultMacroTimeout
+		if macro.Timeout != "" {
+			macro.timeout, err = time.ParseDuration(macro.Timeout)
+			if err != nil {
+				return nil, fmt.Errorf("invalid timeout for macro %s: %v", macro.Name, err)
+			}
+		}
+
+		if macro.Expect != nil {
+			if err := macro.Expect.check(m); err != nil {
+				return nil, fmt.Errorf("macro %s: %v", macro.Name, err)
+			}
+		}
+
+		for j := range macro.Steps {
+			step := &macro.Steps[j]
+
+			if step.Press == "" && step.Until == nil {
+				return nil, fmt.Errorf("step %d of macro %s needs a button to press or a condition to wait for", j+1, macro.Name)
+			}
+
+			if step.Press != "" {
+				button, ok := votes.ButtonForName(step.Press)
+				if !ok {
+					return nil, fmt.Errorf("unknown button %s in macro %s", step.Press, macro.Name)
+				}
+
+				step.button = button
+			}
+
+			if step.Until != nil {
+				if err := step.Until.check(m); err != nil {
+					return nil, fmt.Errorf("macro %s: %v", macro.Name, err)
+				}
+			}
+
+			if step.Max == 0 {
+				step.Max = defaultMacroStepPresses
+			}
+		}
+	}
+
+	return macros, nil
+}
+
+// Runs the macro's steps, returning an error if one of them or the final check
+// fails.
+func (g *Game) runMacro(macro *Macro) error {
+	for i, step := range macro.Steps {
+		var err error
+
+		switch {
+		case step.Until == nil:
+			g.pressButton(step.button)
+		case step.Press == "":
+			err = g.waitFor(*step.Until, macro.timeout)
+		default:
+			err = g.pressUntil(step.button, *step.Until, step.Max)
+		}
+
+		if err != nil {
+			return fmt.Errorf("step %d: %v", i+1, err)
+		}
+	}
+
+	if macro.Expect == nil {
+		return nil
+	}
+
+	if err := g.waitFor(*macro.Expect, macro.timeout); err != nil {
+		return fmt.Errorf("didn't reach the expected screen: %v", err)
+	}
+
+	return nil
+}
+
+func (g *Game) pressUntil(button int, until Condition, max int) error {
+	for presses := 0; !until.matches(g.Emulator.Watches()); presses++ {
+		if presses == max {
+			return fmt.Errorf("%s isn't one of %v after %d presses", until.Watch, until.Values, max)
+		}
+
+		g.pressButton(button)
+	}
+
+	return nil
+}
+
+func (g *Game) waitFor(condition Condition, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	for !condition.matches(g.Emulator.Watches()) {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%s isn't one of %v after %s", condition.Watch, condition.Values, timeout)
+		}
+
+		time.Sleep(screenInterval)
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/zachlatta/nostalgic-rewind/emulator"
+	"github.com/zachlatta/nostalgic-rewind/facebook"
+)
+
+const (
+	milestoneInterval = time.Second
+
+	// How long milestones are announced on the overlay for.
+	announcementDuration = 30 * time.Second
+)
+
+// A point in the story, reached once a watch in the memory map gets to
+// AtLeast. See memory-maps/final-fantasy-milestones.json.
+type Milestone struct {
+	Id      string `json:"id"`
+	Name    string `json:"name"`
+	Watch   string `json:"watch"`
+	AtLeast uint32 `json:"at_least"`
+}
+
+// A milestone the run has reached, as kept in the save.
+type ReachedMilestone struct {
+	Id   string    `json:"id"`
+	Name string    `json:"name"`
+	At   time.Time `json:"at"`
+}
+
+// Loads a JSON list of Milestones, checking that the memory map has the
+// watches they need.
+func LoadMilestones(path string, m emulator.MemoryMap) ([]Milestone, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var milestones []Milestone
+	if err := json.Unmarshal(data, &milestones); err != nil {
+		return nil, err
+	}
+
+	ids := map[string]bool{}
+
+	for _, milestone := range milestones {
+		if milestone.Id == "" || ids[milestone.Id] {
+			return nil, fmt.Errorf("milestone %q needs a unique ID", milestone.Name)
+		}
+		ids[milestone.Id] = true
+
+		if _, ok := m.Watches[milestone.Watch]; !ok {
+			return nil, fmt.Errorf("milestone %s uses %s, which isn't in the memory map", milestone.Id, milestone.Watch)
+		}
+	}
+
+	return milestones, nil
+}
+
+// Checks for newly reached milestones as the game runs.
+func (g *Game) watchMilestones() {
+	ticker := time.NewTicker(milestoneInterval)
+
+	var lastFrame uint64
+	var announcedAt time.Time
+	first := true
+
+	for range ticker.C {
+		if !announcedAt.IsZero() && time.Since(announcedAt) > announcementDuration {
+			announcedAt = time.Time{}
+			g.Obs.UpdateAnnouncement("")
+		}
+
+		watches := g.Emulator.Watches()
+		if watches.Frame == lastFrame {
+			continue
+		}
+		lastFrame = watches.Frame
+
+		reached := g.reachMilestones(watches)
+
+		// Milestones reached before the stream started, like in an imported save,
+		// are recorded without fanfare.
+		if first {
+			first = false
+
+			for _, milestone := range reached {
+				fmt.Println("Milestone already reached:", milestone.Name)
+			}
+
+			continue
+		}
+
+		for _, milestone := range reached {
+			fmt.Println("Milestone reached:", milestone.Name)
+
+			announcedAt = time.Now()
+			g.Obs.UpdateAnnouncement("Milestone: " + milestone.Name + "!")
+
+			if g.PostMilestones {
+				go g.postMilestone(milestone)
+			}
+		}
+	}
+}
+
+// Records and returns the milestones that have been reached since the last
+// check.
+func (g *Game) reachMilestones(watches emulator.WatchSnapshot) []ReachedMilestone {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	reachedIds := map[string]bool{}
+	for _, milestone := range g.reachedMilestones {
+		reachedIds[milestone.Id] = true
+	}
+
+	var reached []ReachedMilestone
+
+	for _, milestone := range g.Milestones {
+		value, ok := watches.Uint(milestone.Watch)
+		if reachedIds[milestone.Id] || !ok || value < milestone.AtLeast {
+			continue
+		}
+
+		r := ReachedMilestone{
+			Id:   milestone.Id,
+			Name: milestone.Name,
+			At:   time.Now(),
+		}
+
+		g.reachedMilestones = append(g.reachedMilestones, r)
+		reached = append(reached, r)
+	}
+
+	return reached
+}
+
+func (g *Game) postMilestone(milestone ReachedMilestone) {
+	uptime := g.uptime().Truncate(time.Minute)
+	message := fmt.Sprintf("Milestone reached after %s: %s!", uptime, milestone.Name)
+
+	if _, err := facebook.PostComment(g.Video.Id, g.AccessToken, message); err != nil {
+		fmt.Fprintln(os.Stderr, "Error posting milestone:", err)
+	}
+}
@@ -0,0 +1,442 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/paked/nes/nes"
+	"github.com/zachlatta/nostalgic-rewind/emulator"
+	"github.com/zachlatta/nostalgic-rewind/votes"
+)
+
+const (
+	nameEntryInterval   = time.Second
+	defaultNameVoteTime = time.Minute
+)
+
+// Lets viewers vote for names (and, for games that have them, classes) by
+// comment instead of spelling them out one press at a time, then types the
+// winner on the game's on-screen keyboard. Loaded from JSON like:
+//
+//	{
+//		"when": {"watch": "name_entry", "values": [1]},
+//		"cursor_x": "name_cursor_x",
+//		"cursor_y": "name_cursor_y",
+//		"keyboard": ["ABCDEFGHIJ", "KLMNOPQRST", "UVWXYZ"],
+//		"max_length": 4,
+//		"select": "a",
+//		"done": ["start"],
+//		"vote_time": "1m",
+//		"slots": 4,
+//		"class": {
+//			"watches": ["slot0_class", "slot1_class", "slot2_class", "slot3_class"],
+//			"classes": ["fighter", "thief", "black_belt"],
+//			"next": "down",
+//			"select": "a"
+//		}
+//	}
+//
+// Each character in keyboard is a key, laid out as on screen, and the cursor
+// watches must hold the column and row of the key the cursor is on. Games that
+// name several characters in a row, like a party, set slots, and each slot
+// gets a class vote (if class is set) followed by a name vote.
+type NameEntry struct {
+	When      Condition `json:"when"`
+	CursorX   string    `json:"cursor_x"`
+	CursorY   string    `json:"cursor_y"`
+	Keyboard  []string  `json:"keyboard"`
+	MaxLength int       `json:"max_length"`
+
+	// Buttons that type the key under the cursor and finish the name
+	Select string   `json:"select"`
+	Done   []string `json:"done"`
+
+	// How long viewers have to vote. Extended until someone votes.
+	VoteTime string `json:"vote_time"`
+
+	// Number of characters to name. Defaults to 1.
+	Slots int             `json:"slots"`
+	Class *ClassSelection `json:"class"`
+
+	selectButton int
+	doneButtons  []int
+	voteTime     time.Duration
+	keys         map[rune][2]uint32
+	width        int
+}
+
+// Picks a character's class before it's named. Each watch holds the class of
+// one slot, as an index into classes, and next cycles through them.
+type ClassSelection struct {
+	Watches []string `json:"watches"`
+	Classes []string `json:"classes"`
+	Next    string   `json:"next"`
+	Select  string   `json:"select"`
+
+	nextButton   int
+	selectButton int
+}
+
+// Vote viewers take part in with comments like "!name BOB" or "!class thief".
+type entryVote struct {
+	// Word the vote is for, which comments start with after a "!"
+	what      string
+	normalize func(string) (string, bool)
+
+	// Choices keyed by user ID
+	votes map[string]string
+}
+
+// Loads a NameEntry, checking that the memory map has the watches it needs.
+func LoadNameEntry(path string, m emulator.MemoryMap) (*NameEntry, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var n NameEntry
+	if err := json.Unmarshal(data, &n); err != nil {
+		return nil, err
+	}
+
+	for _, watch := range []string{n.When.Watch, n.CursorX, n.CursorY} {
+		if _, ok := m.Watches[watch]; !ok {
+			return nil, fmt.Errorf("%s isn't in the memory map", watch)
+		}
+	}
+
+	if len(n.Keyboard) == 0 || n.MaxLength <= 0 {
+		return nil, errors.New("name entry needs a keyboard and max length")
+	}
+
+	var ok bool
+	if n.selectButton, ok = votes.ButtonForName(n.Select); !ok {
+		return nil, fmt.Errorf("unknown button %s", n.Select)
+	}
+
+	for _, name := range n.Done {
+		button, ok := votes.ButtonForName(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown button %s", name)
+		}
+
+		n.doneButtons = append(n.doneButtons, button)
+	}
+
+	n.voteTime = defaultNameVoteTime
+	if n.VoteTime != "" {
+		n.voteTime, err = time.ParseDuration(n.VoteTime)
+		if err != nil {
+			return nil, fmt.Errorf("invalid vote time: %v", err)
+		}
+	}
+
+	if n.Slots == 0 {
+		n.Slots = 1
+	}
+
+	if n.Class != nil {
+		if err := n.Class.load(m, n.Slots); err != nil {
+			return nil, err
+		}
+	}
+
+	n.keys = map[rune][2]uint32{}
+
+	for y, row := range n.Keyboard {
+		keys := []rune(row)
+
+		for x, key := range keys {
+			if _, ok := n.keys[key]; !ok {
+				n.keys[key] = [2]uint32{uint32(x), uint32(y)}
+			}
+		}
+
+		if len(keys) > n.width {
+			n.width = len(keys)
+		}
+	}
+
+	return &n, nil
+}
+
+// Returns the name as it can be typed on the keyboard, upper casing it if the
+// keyboard only has capitals, and whether or not it can be.
+func (n *NameEntry) normalize(name string) (string, bool) {
+	if name == "" || len([]rune(name)) > n.MaxLength || strings.Contains(name, " ") {
+		return "", false
+	}
+
+	for _, candidate := range []string{name, strings.ToUpper(name)} {
+		typeable := true
+
+		for _, r := range candidate {
+			if _, ok := n.keys[r]; !ok {
+				typeable = false
+				break
+			}
+		}
+
+		if typeable {
+			return candidate, true
+		}
+	}
+
+	return "", false
+}
+
+func (c *ClassSelection) load(m emulator.MemoryMap, slots int) error {
+	if len(c.Classes) == 0 || len(c.Watches) < slots {
+		return fmt.Errorf("class selection needs classes and a watch for each of the %d slots", slots)
+	}
+
+	for _, watch := range c.Watches {
+		if _, ok := m.Watches[watch]; !ok {
+			return fmt.Errorf("%s isn't in the memory map", watch)
+		}
+	}
+
+	var ok bool
+	if c.nextButton, ok = votes.ButtonForName(c.Next); !ok {
+		return fmt.Errorf("unknown button %s", c.Next)
+	}
+
+	if c.selectButton, ok = votes.ButtonForName(c.Select); !ok {
+		return fmt.Errorf("unknown button %s", c.Select)
+	}
+
+	return nil
+}
+
+// Returns the class as it's spelled in the list of classes and whether or not
+// it's one of them.
+func (c *ClassSelection) normalize(class string) (string, bool) {
+	for _, candidate := range c.Classes {
+		if strings.EqualFold(class, candidate) {
+			return candidate, true
+		}
+	}
+
+	return "", false
+}
+
+func (c *ClassSelection) index(class string) uint32 {
+	for i, candidate := range c.Classes {
+		if candidate == class {
+			return uint32(i)
+		}
+	}
+
+	return 0
+}
+
+// Records a viewer's vote if the comment is one for the running vote,
+// replacing any earlier vote. Ignored when no vote is running.
+func (g *Game) voteForEntry(userId, message string) {
+	fields := strings.Fields(message)
+	if len(fields) != 2 {
+		return
+	}
+
+	// Votes are kept per viewer, so every unidentified commenter would
+	// replace the last one's. See voteForCheat.
+	if userId == "" {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	vote := g.entryVote
+	if vote == nil || !strings.EqualFold(fields[0], "!"+vote.what) {
+		return
+	}
+
+	if choice, ok := vote.normalize(fields[1]); ok {
+		vote.votes[userId] = choice
+	}
+}
+
+// Returns the choice with the most votes and how many it has, breaking ties
+// alphabetically.
+func (v *entryVote) leading() (string, int) {
+	counts := map[string]int{}
+	for _, choice := range v.votes {
+		counts[choice] += 1
+	}
+
+	var choices []string
+	for choice := range counts {
+		choices = append(choices, choice)
+	}
+	sort.Strings(choices)
+
+	var leading string
+	for _, choice := range choices {
+		if counts[choice] > counts[leading] {
+			leading = choice
+		}
+	}
+
+	return leading, counts[leading]
+}
+
+// Runs the class and name votes whenever the game is on its name entry screen,
+// pausing regular button votes until the winners have been entered.
+func (g *Game) watchNameEntry() {
+	n := g.NameEntry
+	ticker := time.NewTicker(nameEntryInterval)
+
+	var deadline time.Time
+	slot := 0
+	naming := n.Class == nil
+
+	for range ticker.C {
+		onScreen := n.When.matches(g.Emulator.Watches())
+
+		if !onScreen {
+			g.mu.Lock()
+			running := g.entryVote != nil
+			g.entryVote = nil
+			g.mu.Unlock()
+
+			if running {
+				g.Obs.UpdateAnnouncement("")
+			}
+
+			slot = 0
+			naming = n.Class == nil
+			continue
+		}
+
+		if slot >= n.Slots {
+			continue
+		}
+
+		g.mu.Lock()
+		if g.entryVote == nil {
+			g.entryVote = &entryVote{what: "name", normalize: n.normalize, votes: map[string]string{}}
+			if !naming {
+				g.entryVote = &entryVote{what: "class", normalize: n.Class.normalize, votes: map[string]string{}}
+			}
+
+			fmt.Printf("Voting on the %s for slot %d\n", g.entryVote.what, slot+1)
+			deadline = time.Now().Add(n.voteTime)
+		}
+		what := g.entryVote.what
+		leading, count := g.entryVote.leading()
+		g.mu.Unlock()
+
+		if time.Now().Before(deadline) {
+			message := fmt.Sprintf("Pick a %s! Comment !%s %s (%ds left)", what, what, strings.ToUpper(what), int(time.Until(deadline).Seconds()))
+			if !naming {
+				message += "\nClasses: " + strings.Join(n.Class.Classes, ", ")
+			}
+			if leading != "" {
+				message += fmt.Sprintf("\nLeading: %s (%d)", leading, count)
+			}
+
+			g.Obs.UpdateAnnouncement(message)
+			continue
+		}
+
+		if leading == "" {
+			fmt.Printf("No %ss proposed. Extending the vote.\n", what)
+			deadline = time.Now().Add(n.voteTime)
+			continue
+		}
+
+		fmt.Printf("Entering %s %s with %d votes\n", what, leading, count)
+		g.Obs.UpdateAnnouncement("Chosen " + what + ": " + leading + "!")
+		g.Obs.AddMostRecentPress(what + " " + leading)
+
+		var err error
+
+		g.inputMu.Lock()
+		if naming {
+			err = g.enterName(leading)
+		} else {
+			err = g.enterClass(slot, leading)
+		}
+		g.inputMu.Unlock()
+
+		g.mu.Lock()
+		g.entryVote = nil
+		g.mu.Unlock()
+
+		g.Obs.UpdateAnnouncement("")
+
+		if err != nil {
+			fmt.Printf("Entering %s %s failed: %v. Voting again.\n", what, leading, err)
+			continue
+		}
+
+		if naming {
+			slot++
+			naming = n.Class == nil
+		} else {
+			naming = true
+		}
+	}
+}
+
+// Cycles the slot's class until it's the given one, then selects it. Caller must
+// hold g.inputMu.
+func (g *Game) enterClass(slot int, class string) error {
+	c := g.NameEntry.Class
+	until := Condition{Watch: c.Watches[slot], Values: []uint32{c.index(class)}}
+
+	if err := g.pressUntil(c.nextButton, until, len(c.Classes)); err != nil {
+		return err
+	}
+
+	g.pressButton(c.selectButton)
+
+	return nil
+}
+
+// Types the name on the on-screen keyboard, checking the cursor reaches each
+// key before selecting it. Caller must hold g.inputMu.
+func (g *Game) enterName(name string) error {
+	n := g.NameEntry
+
+	for _, r := range name {
+		key := n.keys[r]
+
+		if err := g.moveCursor(n.CursorY, key[1], nes.ButtonUp, nes.ButtonDown, len(n.Keyboard)); err != nil {
+			return err
+		}
+
+		if err := g.moveCursor(n.CursorX, key[0], nes.ButtonLeft, nes.ButtonRight, n.width); err != nil {
+			return err
+		}
+
+		g.pressButton(n.selectButton)
+	}
+
+	for _, button := range n.doneButtons {
+		g.pressButton(button)
+	}
+
+	return nil
+}
+
+// Presses back or forward until the watch reaches the value.
+func (g *Game) moveCursor(watch string, to uint32, back, forward, max int) error {
+	current, ok := g.Emulator.Watches().Uint(watch)
+	if !ok {
+		return fmt.Errorf("%s hasn't been read yet", watch)
+	}
+
+	button := forward
+	if to < current {
+		button = back
+	}
+
+	return g.pressUntil(button, Condition{Watch: watch, Values: []uint32{to}}, max)
+}
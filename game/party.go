@@ -0,0 +1,131 @@
+package game
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/zachlatta/nostalgic-rewind/emulator"
+	"github.com/zachlatta/nostalgic-rewind/obs"
+)
+
+// How often the party overlay is redrawn.
+const partyInterval = time.Second / 4
+
+// Abbreviated, in the order Final Fantasy numbers them.
+var partyClasses = []string{
+	"FI", "TH", "BB", "RM", "WM", "BM",
+	"KN", "NI", "MA", "RW", "WW", "BW",
+}
+
+var partyAilments = []struct {
+	bit  uint32
+	name string
+}{
+	{0x01, "KO"},
+	{0x02, "STN"},
+	{0x04, "PSN"},
+	{0x08, "BLN"},
+	{0x10, "PAR"},
+	{0x20, "SLP"},
+	{0x40, "MUT"},
+	{0x80, "CNF"},
+}
+
+// Returns whether the memory map has the watches the party overlay needs.
+// See memory-maps/final-fantasy.json.
+func hasParty(m *emulator.MemoryMap) bool {
+	if m == nil {
+		return false
+	}
+
+	_, ok := m.Watches["party0.name"]
+	return ok
+}
+
+// Keeps the party overlay up to date while the game runs.
+func (g *Game) showParty() {
+	ticker := time.NewTicker(partyInterval)
+
+	var lastFrame uint64
+
+	for range ticker.C {
+		watches := g.Emulator.Watches()
+		if watches.Frame == lastFrame {
+			continue
+		}
+		lastFrame = watches.Frame
+
+		gold, _ := watches.Uint("gold")
+
+		if err := g.Obs.UpdateParty(readParty(watches), int(gold)); err != nil {
+			fmt.Fprintln(os.Stderr, "Error updating party on overlay:", err)
+		}
+	}
+}
+
+func readParty(watches emulator.WatchSnapshot) []obs.PartyMember {
+	var party []obs.PartyMember
+
+	for i := 0; ; i++ {
+		prefix := fmt.Sprintf("party%d.", i)
+
+		name, ok := watches.Bytes(prefix + "name")
+		if !ok {
+			break
+		}
+
+		member := obs.PartyMember{Name: decodeText(name)}
+
+		if class, ok := watches.Uint(prefix + "class"); ok && int(class) < len(partyClasses) {
+			member.Class = partyClasses[class]
+		}
+
+		// Levels are counted from 0.
+		level, _ := watches.Uint(prefix + "level")
+		member.Level = int(level) + 1
+
+		hp, _ := watches.Uint(prefix + "hp")
+		maxHP, _ := watches.Uint(prefix + "max_hp")
+		member.HP, member.MaxHP = int(hp), int(maxHP)
+
+		// Spells are cast with charges for each spell level, which are added up.
+		mp, _ := watches.Bytes(prefix + "mp")
+		for _, charges := range mp {
+			member.MP += int(charges)
+		}
+
+		ailments, _ := watches.Uint(prefix + "ailments")
+		for _, ailment := range partyAilments {
+			if ailments&ailment.bit != 0 {
+				member.Ailments = append(member.Ailments, ailment.name)
+			}
+		}
+
+		party = append(party, member)
+	}
+
+	return party
+}
+
+// Decodes text in Final Fantasy's character set, where digits start at 0x80
+// and letters follow them.
+func decodeText(data []byte) string {
+	var b strings.Builder
+
+	for _, c := range data {
+		switch {
+		case c >= 0x80 && c <= 0x89:
+			b.WriteByte('0' + c - 0x80)
+		case c >= 0x8a && c <= 0xa3:
+			b.WriteByte('A' + c - 0x8a)
+		case c >= 0xa4 && c <= 0xbd:
+			b.WriteByte('a' + c - 0xa4)
+		default:
+			b.WriteByte(' ')
+		}
+	}
+
+	return strings.TrimRight(b.String(), " ")
+}
@@ -0,0 +1,137 @@
+package game
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/zachlatta/nostalgic-rewind/util"
+)
+
+// Writes a savestate and game save the way Game.Save does.
+func writeSavePair(t *testing.T, dir, state, romPath string) {
+	statePath := filepath.Join(dir, nesSaveFileName)
+	if err := util.WriteDataSafely(statePath, []byte(state)); err != nil {
+		t.Fatal(err)
+	}
+
+	checksum, err := util.FileChecksum(statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(Save{RomPath: romPath, SavestateChecksum: checksum})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := util.WriteDataSafely(filepath.Join(dir, GameSavePath), data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func loadSavePair(t *testing.T, dir string) (Save, string) {
+	save, err := LoadSave(filepath.Join(dir, GameSavePath))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := ioutil.ReadFile(save.savestatePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return save, string(state)
+}
+
+func TestLoadSaveLatestPair(t *testing.T) {
+	dir := t.TempDir()
+	writeSavePair(t, dir, "state one", "one.nes")
+	writeSavePair(t, dir, "state two", "two.nes")
+
+	save, state := loadSavePair(t, dir)
+	if save.RomPath != "two.nes" || state != "state two" {
+		t.Errorf("expected the latest pair, got %s with %q", save.RomPath, state)
+	}
+}
+
+func TestLoadSaveFallsBackAsAPair(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt string
+	}{
+		{"corrupt savestate", nesSaveFileName},
+		{"corrupt game save", GameSavePath},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeSavePair(t, dir, "state one", "one.nes")
+			writeSavePair(t, dir, "state two", "two.nes")
+
+			if err := ioutil.WriteFile(filepath.Join(dir, test.corrupt), []byte("corrupt"), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			save, state := loadSavePair(t, dir)
+			if save.RomPath != "one.nes" || state != "state one" {
+				t.Errorf("expected the previous pair, got %s with %q", save.RomPath, state)
+			}
+		})
+	}
+}
+
+// Saving writes the savestate before the game save, so a crash in between
+// leaves the latest savestate next to the game save from before it.
+func TestLoadSaveAfterCrashBetweenFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeSavePair(t, dir, "state one", "one.nes")
+
+	if err := util.WriteDataSafely(filepath.Join(dir, nesSaveFileName), []byte("state two")); err != nil {
+		t.Fatal(err)
+	}
+
+	save, state := loadSavePair(t, dir)
+	if save.RomPath != "one.nes" || state != "state one" {
+		t.Errorf("expected the savestate saved with the game save, got %s with %q", save.RomPath, state)
+	}
+}
+
+func TestFindRomSaveDirLeavesLegacySaves(t *testing.T) {
+	savePath := t.TempDir()
+
+	romPath := filepath.Join(t.TempDir(), "game.nes")
+	if err := ioutil.WriteFile(romPath, []byte("rom"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	legacyDir := legacyRomSaveDir(savePath, romPath)
+	if err := os.MkdirAll(legacyDir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := FindRomSaveDir(savePath, romPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dir != legacyDir {
+		t.Errorf("expected %s, got %s", legacyDir, dir)
+	}
+
+	if exists, err := util.FileExists(legacyDir); err != nil || !exists {
+		t.Errorf("expected the saves keyed by path to be left in place, got %t, %v", exists, err)
+	}
+
+	moved, err := RomSaveDir(savePath, romPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dir, err := FindRomSaveDir(savePath, romPath); err != nil || dir != moved {
+		t.Errorf("expected %s once the saves are moved, got %s, %v", moved, dir, err)
+	}
+}
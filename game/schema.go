@@ -0,0 +1,187 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Version of the Save schema written by this build. Bump it whenever Save
+// gains or changes a field, adding a migration to saveMigrations and a
+// testdata/save-vN.json written by the previous version for the tests.
+const CurrentSaveVersion = 7
+
+// saveMigrations[i] upgrades a raw save from version i to version i+1.
+var saveMigrations = []func(raw map[string]interface{}) error{
+	migrateSaveV0,
+	migrateSaveV1,
+	migrateSaveV2,
+	migrateSaveV3,
+	migrateSaveV4,
+	migrateSaveV5,
+	migrateSaveV6,
+}
+
+// Decodes a game save, upgrading it to the current schema version first if it
+// was written by an older version.
+func DecodeSave(data []byte) (Save, error) {
+	var save Save
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return save, err
+	}
+
+	version, err := saveVersion(raw)
+	if err != nil {
+		return save, err
+	}
+
+	if version > CurrentSaveVersion {
+		return save, fmt.Errorf("save is version %d, but this build only understands up to version %d", version, CurrentSaveVersion)
+	}
+
+	for ; version < CurrentSaveVersion; version++ {
+		if err := saveMigrations[version](raw); err != nil {
+			return save, fmt.Errorf("error migrating save from version %d: %v", version, err)
+		}
+
+		raw["version"] = version + 1
+	}
+
+	migrated, err := json.Marshal(raw)
+	if err != nil {
+		return save, err
+	}
+
+	err = json.Unmarshal(migrated, &save)
+
+	return save, err
+}
+
+// Saves written before versioning was added have no version field and are
+// version 0.
+func saveVersion(raw map[string]interface{}) (int, error) {
+	rawVersion, ok := raw["version"]
+	if !ok {
+		return 0, nil
+	}
+
+	version, ok := rawVersion.(float64)
+	if !ok || version < 0 || version != float64(int(version)) {
+		return 0, fmt.Errorf("invalid save version %v", rawVersion)
+	}
+
+	return int(version), nil
+}
+
+// Version 0 saves predate viewer statistics.
+func migrateSaveV0(raw map[string]interface{}) error {
+	if _, ok := raw["viewer_sessions"]; !ok {
+		raw["viewer_sessions"] = []interface{}{}
+	}
+
+	return nil
+}
+
+// Version 1 saves were kept in a directory named after the ROM's path. The
+// ROM's hash is filled in once the game is loaded with it.
+func migrateSaveV1(raw map[string]interface{}) error {
+	if _, ok := raw["rom_hash"]; !ok {
+		raw["rom_hash"] = ""
+	}
+
+	return nil
+}
+
+// Version 2 saves predate the lifetime counters. Uptime wasn't saved, but
+// every session the stream ran for was, so it's rebuilt from those.
+func migrateSaveV2(raw map[string]interface{}) error {
+	if _, ok := raw["uptime"]; !ok {
+		var uptime time.Duration
+
+		sessions, _ := raw["viewer_sessions"].([]interface{})
+		for _, rawSession := range sessions {
+			session, ok := rawSession.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("invalid viewer session %v", rawSession)
+			}
+
+			start, err := parseSessionTime(session["start"])
+			if err != nil {
+				return err
+			}
+
+			end, err := parseSessionTime(session["end"])
+			if err != nil {
+				return err
+			}
+
+			if end.After(start) {
+				uptime += end.Sub(start)
+			}
+		}
+
+		raw["uptime"] = int64(uptime)
+	}
+
+	defaults := map[string]interface{}{
+		"button_presses":      0,
+		"most_recent_presses": []interface{}{},
+		"play_time":           0,
+	}
+
+	for field, value := range defaults {
+		if _, ok := raw[field]; !ok {
+			raw[field] = value
+		}
+	}
+
+	return nil
+}
+
+// Version 3 saves predate ROM patches, so were always of the unpatched ROM.
+func migrateSaveV3(raw map[string]interface{}) error {
+	if _, ok := raw["patches"]; !ok {
+		raw["patches"] = []interface{}{}
+	}
+
+	return nil
+}
+
+// Version 4 saves predate cheats.
+func migrateSaveV4(raw map[string]interface{}) error {
+	if _, ok := raw["active_cheats"]; !ok {
+		raw["active_cheats"] = []interface{}{}
+	}
+
+	return nil
+}
+
+// Version 5 saves predate milestones.
+func migrateSaveV5(raw map[string]interface{}) error {
+	if _, ok := raw["milestones"]; !ok {
+		raw["milestones"] = []interface{}{}
+	}
+
+	return nil
+}
+
+// Version 6 saves predate run stats. They can't be rebuilt, so the run's
+// stats start from when it's next played.
+func migrateSaveV6(raw map[string]interface{}) error {
+	if _, ok := raw["stats"]; !ok {
+		raw["stats"] = map[string]interface{}{"inputs": map[string]interface{}{}}
+	}
+
+	return nil
+}
+
+func parseSessionTime(rawTime interface{}) (time.Time, error) {
+	str, ok := rawTime.(string)
+	if !ok {
+		return time.Time{}, fmt.Errorf("invalid viewer session time %v", rawTime)
+	}
+
+	return time.Parse(time.RFC3339Nano, str)
+}
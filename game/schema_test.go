@@ -0,0 +1,113 @@
+package game
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "Rewrite the golden files in testdata")
+
+// Each testdata/save-vN.json is a game.json as written by a build using
+// version N of the schema. Decoding it must give the save in
+// testdata/save-vN.golden.json.
+func TestDecodeSaveGolden(t *testing.T) {
+	for version := 0; version < CurrentSaveVersion; version++ {
+		name := fmt.Sprintf("save-v%d", version)
+
+		t.Run(name, func(t *testing.T) {
+			data, err := ioutil.ReadFile(filepath.Join("testdata", name+".json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			save, err := DecodeSave(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if save.Version != CurrentSaveVersion {
+				t.Errorf("expected version %d, got %d", CurrentSaveVersion, save.Version)
+			}
+
+			decoded, err := json.MarshalIndent(save, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			goldenPath := filepath.Join("testdata", name+".golden.json")
+
+			if *update {
+				if err := ioutil.WriteFile(goldenPath, append(decoded, '\n'), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			golden, err := ioutil.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(bytes.TrimSpace(golden), decoded) {
+				t.Errorf("decoded save doesn't match %s:\n%s", goldenPath, decoded)
+			}
+		})
+	}
+}
+
+func TestDecodeSaveRebuildsUptime(t *testing.T) {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", "save-v2.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	save, err := DecodeSave(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := 90 * time.Minute; save.Uptime != expected {
+		t.Errorf("expected uptime %s, got %s", expected, save.Uptime)
+	}
+}
+
+func TestDecodeSaveCurrentVersion(t *testing.T) {
+	save := Save{
+		Version:  CurrentSaveVersion,
+		RomPath:  "roms/final-fantasy.nes",
+		Uptime:   time.Hour,
+		PlayTime: time.Minute,
+		Patches:  []string{"translation.ips"},
+		ViewerSessions: []ViewerSession{
+			{Peak: 12, Average: 8.5, Samples: 720},
+		},
+	}
+
+	data, err := json.Marshal(save)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := DecodeSave(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.RomPath != save.RomPath || decoded.Uptime != save.Uptime || decoded.PlayTime != save.PlayTime ||
+		len(decoded.Patches) != 1 || len(decoded.ViewerSessions) != 1 || decoded.ViewerSessions[0] != save.ViewerSessions[0] {
+		t.Errorf("expected %+v, got %+v", save, decoded)
+	}
+}
+
+func TestDecodeSaveFromTheFuture(t *testing.T) {
+	data := []byte(fmt.Sprintf(`{"version": %d}`, CurrentSaveVersion+1))
+
+	if _, err := DecodeSave(data); err == nil {
+		t.Error("expected an error decoding a save newer than this build")
+	}
+}
@@ -0,0 +1,236 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zachlatta/nostalgic-rewind/emulator"
+	"github.com/zachlatta/nostalgic-rewind/facebook"
+	"github.com/zachlatta/nostalgic-rewind/obs"
+	"github.com/zachlatta/nostalgic-rewind/votes"
+)
+
+const screenInterval = time.Second / 10
+
+// Something viewers can vote for: one or more buttons pressed in order, or a
+// macro.
+type Action struct {
+	Name    string
+	Buttons []int
+	Macro   *Macro
+}
+
+func buttonAction(button int) Action {
+	return Action{
+		Name:    buttonToString[button],
+		Buttons: []int{button},
+	}
+}
+
+// Controls used instead of the usual reaction to button mapping while the
+// game is on a particular screen, detected from a watch in the memory map.
+// Loaded from a JSON list like:
+//
+//	[{
+//		"name": "battle",
+//		"when": {"watch": "in_battle", "values": [1]},
+//		"actions": {
+//			"LIKE": {"name": "fight", "buttons": ["a"]},
+//			"LOVE": {"name": "magic", "buttons": ["down", "a"]},
+//			"HAHA": {"name": "item", "buttons": ["down", "down", "down", "a"]},
+//			"WOW": {"name": "run", "buttons": ["right", "a"]},
+//			"SAD": {"name": "back", "buttons": ["b"]},
+//			"ANGRY": {"name": "attack", "macro": "attack_first_enemy"}
+//		}
+//	}]
+//
+// The first set whose watch has one of its values is used. Reactions left out
+// of a set don't vote for anything on that screen. Actions name either buttons
+// or one of the macros passed to LoadButtonSets.
+type ButtonSet struct {
+	Name string    `json:"name"`
+	When Condition `json:"when"`
+
+	// Key is the reaction's name
+	Actions map[string]struct {
+		Name    string   `json:"name"`
+		Buttons []string `json:"buttons"`
+		Macro   string   `json:"macro"`
+	} `json:"actions"`
+
+	actions map[facebook.ReactionType]Action
+}
+
+// Holds when a watch has one of the values.
+type Condition struct {
+	Watch  string   `json:"watch"`
+	Values []uint32 `json:"values"`
+}
+
+func (c Condition) check(m emulator.MemoryMap) error {
+	if _, ok := m.Watches[c.Watch]; !ok {
+		return fmt.Errorf("%s isn't in the memory map", c.Watch)
+	}
+
+	return nil
+}
+
+func (c Condition) matches(watches emulator.WatchSnapshot) bool {
+	value, ok := watches.Uint(c.Watch)
+	if !ok {
+		return false
+	}
+
+	for _, v := range c.Values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Loads a JSON list of ButtonSets, checking that the memory map has the
+// watches they need and that the macros they use exist.
+func LoadButtonSets(path string, m emulator.MemoryMap, macros []Macro) ([]ButtonSet, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var sets []ButtonSet
+	if err := json.Unmarshal(data, &sets); err != nil {
+		return nil, err
+	}
+
+	for i := range sets {
+		set := &sets[i]
+
+		if err := set.When.check(m); err != nil {
+			return nil, fmt.Errorf("button set %s: %v", set.Name, err)
+		}
+
+		set.actions = map[facebook.ReactionType]Action{}
+
+		for reactionName, config := range set.Actions {
+			reaction, ok := facebook.ReactionTypeForName(reactionName)
+			if !ok {
+				return nil, fmt.Errorf("unknown reaction %s in button set %s", reactionName, set.Name)
+			}
+
+			action := Action{Name: config.Name}
+
+			if config.Macro != "" {
+				if len(config.Buttons) > 0 {
+					return nil, fmt.Errorf("%s in button set %s has both buttons and a macro", reactionName, set.Name)
+				}
+
+				for j := range macros {
+					if macros[j].Name == config.Macro {
+						action.Macro = &macros[j]
+					}
+				}
+
+				if action.Macro == nil {
+					return nil, fmt.Errorf("unknown macro %s in button set %s", config.Macro, set.Name)
+				}
+			}
+
+			for _, buttonName := range config.Buttons {
+				button, ok := votes.ButtonForName(buttonName)
+				if !ok {
+					return nil, fmt.Errorf("unknown button %s in button set %s", buttonName, set.Name)
+				}
+
+				action.Buttons = append(action.Buttons, button)
+			}
+
+			if action.Name == "" || (len(action.Buttons) == 0 && action.Macro == nil) {
+				return nil, fmt.Errorf("%s in button set %s needs a name and buttons", reactionName, set.Name)
+			}
+
+			set.actions[reaction] = action
+		}
+	}
+
+	return sets, nil
+}
+
+// Switches button sets as the game changes screens.
+func (g *Game) watchScreen() {
+	ticker := time.NewTicker(screenInterval)
+
+	var lastFrame uint64
+
+	for range ticker.C {
+		watches := g.Emulator.Watches()
+		if watches.Frame == lastFrame {
+			continue
+		}
+		lastFrame = watches.Frame
+
+		var screen *ButtonSet
+		for i := range g.ButtonSets {
+			if g.ButtonSets[i].When.matches(watches) {
+				screen = &g.ButtonSets[i]
+				break
+			}
+		}
+
+		g.mu.Lock()
+		changed := screen != g.screen
+		g.screen = screen
+		g.mu.Unlock()
+
+		if !changed {
+			continue
+		}
+
+		if screen == nil {
+			fmt.Println("Switched to the default controls")
+		} else {
+			fmt.Printf("Switched to the %s controls\n", screen.Name)
+		}
+
+		g.updateVoteBreakdown()
+	}
+}
+
+// Returns what the reaction votes for on the current screen. Caller must hold
+// g.mu.
+func (g *Game) reactionAction(reaction facebook.ReactionType) (Action, bool) {
+	if g.screen != nil {
+		action, ok := g.screen.actions[reaction]
+		return action, ok
+	}
+
+	if button := reactionToButton(reaction); button != -1 {
+		return buttonAction(button), true
+	}
+
+	return Action{}, false
+}
+
+// Returns the votes for each reaction's action on the current screen, for the
+// overlay. Caller must hold g.mu.
+func (g *Game) screenVoteBreakdown(counts map[string]int) []obs.ActionVotes {
+	var breakdown []obs.ActionVotes
+
+	for reactionName, action := range g.screen.Actions {
+		breakdown = append(breakdown, obs.ActionVotes{
+			Reaction: strings.ToUpper(reactionName),
+			Action:   strings.ToUpper(action.Name),
+			Votes:    counts[action.Name],
+		})
+	}
+
+	sort.Slice(breakdown, func(i, j int) bool {
+		return breakdown[i].Reaction < breakdown[j].Reaction
+	})
+
+	return breakdown
+}
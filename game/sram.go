@@ -0,0 +1,166 @@
+package game
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/paked/nes/nes"
+	"github.com/zachlatta/nostalgic-rewind/util"
+)
+
+const (
+	// Battery backed save RAM, in the raw format other emulators use for .sav
+	// files.
+	sramFileName = "save.sav"
+	sramSize     = 0x2000
+
+	sramFlushInterval = 10 * time.Second
+)
+
+var ErrSavestateExists = errors.New("there's already a savestate, which would be loaded instead of the SRAM")
+
+// Writes battery backed save RAM to disk as it changes. Copies are taken on
+// the emulator's thread and written from another goroutine so frames aren't
+// held up by the disk.
+type sramWriter struct {
+	path   string
+	writes chan []byte
+
+	mu          sync.Mutex
+	lastWritten []byte
+	lastCopied  time.Time
+}
+
+func newSRAMWriter(path string) *sramWriter {
+	return &sramWriter{
+		path:   path,
+		writes: make(chan []byte, 1),
+	}
+}
+
+func (w *sramWriter) run() {
+	for sram := range w.writes {
+		if err := w.write(sram); err != nil {
+			fmt.Fprintln(os.Stderr, "Error saving SRAM:", err)
+		}
+	}
+}
+
+// Called every frame. Queues a copy of the SRAM to be written every
+// sramFlushInterval, dropping it if the last copy hasn't been written yet.
+func (w *sramWriter) frame(console *nes.Console) {
+	if !hasBattery(console) || time.Since(w.lastCopied) < sramFlushInterval {
+		return
+	}
+
+	w.lastCopied = time.Now()
+
+	select {
+	case w.writes <- append([]byte{}, console.Cartridge.SRAM...):
+	default:
+	}
+}
+
+// Writes the SRAM straight away, for when the emulator is stopping.
+func (w *sramWriter) flush(console *nes.Console) error {
+	if !hasBattery(console) {
+		return nil
+	}
+
+	return w.write(append([]byte{}, console.Cartridge.SRAM...))
+}
+
+func (w *sramWriter) write(sram []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if bytes.Equal(sram, w.lastWritten) {
+		return nil
+	}
+
+	if err := util.WriteDataSafely(w.path, sram); err != nil {
+		return err
+	}
+
+	w.lastWritten = sram
+
+	return nil
+}
+
+func hasBattery(console *nes.Console) bool {
+	return console.Cartridge != nil && console.Cartridge.Battery != 0
+}
+
+// Seeds the console's SRAM from the .sav in the ROM's save directory when
+// there's no savestate to start from, which already includes the SRAM. Must
+// be called before the first frame.
+func (g *Game) seedSRAM(console *nes.Console) error {
+	if _, err := util.LatestValidFile(g.nesSaveFilePath()); !os.IsNotExist(err) {
+		return err
+	}
+
+	path, err := util.LatestValidFile(filepath.Join(g.saveDir(), sramFileName))
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	sram, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("No savestate yet. Starting from SRAM in:", path)
+
+	copy(console.Cartridge.SRAM, sram)
+
+	return nil
+}
+
+// Replaces the battery backed save RAM in the given ROM save directory with a
+// .sav from another emulator, so the next run boots from the game's own save
+// instead of a savestate. If there's already a savestate, it's kept as a
+// snapshot and removed, but only if force is set.
+func ImportSRAM(romSaveDir string, sram []byte, force bool) error {
+	if len(sram) != sramSize {
+		return fmt.Errorf("SRAM must be %d bytes, got %d", sramSize, len(sram))
+	}
+
+	statePath := filepath.Join(romSaveDir, nesSaveFileName)
+
+	hasState, err := util.FileExists(statePath)
+	if err != nil {
+		return err
+	}
+
+	if hasState {
+		if !force {
+			return ErrSavestateExists
+		}
+
+		snapshot, err := TakeSnapshot(romSaveDir, time.Now())
+		if err != nil {
+			return err
+		}
+
+		fmt.Println("Kept the current saves as snapshot", snapshot.Time.Format(SnapshotTimeLayout))
+
+		// Every generation has to go, otherwise the previous one is loaded.
+		for _, path := range []string{statePath, statePath + util.PreviousExtension} {
+			for _, p := range []string{path, path + util.ChecksumExtension} {
+				if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+					return err
+				}
+			}
+		}
+	}
+
+	return util.WriteDataSafely(filepath.Join(romSaveDir, sramFileName), sram)
+}
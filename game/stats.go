@@ -0,0 +1,188 @@
+package game
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zachlatta/nostalgic-rewind/emulator"
+)
+
+// Often enough to catch every step, which take a quarter of a second.
+const statsInterval = time.Second / 10
+
+// Ailments that take a party member out of the fight for good.
+const incapacitatedAilments = 0x01 | 0x02
+
+// Counters kept over the whole run. The memory based ones need watches in the
+// memory map: "partyN.ailments" for wipes, "in_battle" (non-zero during a
+// battle) and "partyN.exp" for battles, and any number of "position.*" watches
+// for steps.
+type RunStats struct {
+	Wipes       int `json:"wipes"`
+	Battles     int `json:"battles"`
+	BattlesFled int `json:"battles_fled"`
+	Steps       int `json:"steps"`
+
+	// Key is the button's name
+	Inputs map[string]int `json:"inputs"`
+}
+
+// What the last check of the game's memory saw, to compare the next one to.
+type statsState struct {
+	wiped    bool
+	inBattle bool
+	position map[string]uint32
+
+	// Party's total experience, and what it was when the current battle
+	// started. Battles that end without any being gained, and without a wipe,
+	// were fled.
+	exp            uint32
+	battleStartExp uint32
+}
+
+// Keeps the run's stats up to date from the game's memory.
+func (g *Game) trackStats() {
+	ticker := time.NewTicker(statsInterval)
+
+	var lastFrame uint64
+	var last *statsState
+
+	for range ticker.C {
+		watches := g.Emulator.Watches()
+		if watches.Frame == lastFrame {
+			continue
+		}
+		lastFrame = watches.Frame
+
+		state := readStatsState(watches)
+
+		// The first state is what the game was doing when we started.
+		if last != nil {
+			g.mu.Lock()
+			g.stats.update(*last, &state)
+			g.mu.Unlock()
+		}
+
+		last = &state
+	}
+}
+
+func readStatsState(watches emulator.WatchSnapshot) statsState {
+	state := statsState{position: map[string]uint32{}}
+
+	members := 0
+	incapacitated := 0
+
+	for i := 0; ; i++ {
+		ailments, ok := watches.Uint(fmt.Sprintf("party%d.ailments", i))
+		if !ok {
+			break
+		}
+
+		members++
+		if ailments&incapacitatedAilments != 0 {
+			incapacitated++
+		}
+
+		exp, _ := watches.Uint(fmt.Sprintf("party%d.exp", i))
+		state.exp += exp
+	}
+
+	state.wiped = members > 0 && incapacitated == members
+
+	inBattle, _ := watches.Uint("in_battle")
+	state.inBattle = inBattle != 0
+
+	for name, value := range watches.Values() {
+		if v, ok := value.(uint32); ok && strings.HasPrefix(name, "position.") {
+			state.position[name] = v
+		}
+	}
+
+	return state
+}
+
+// Caller must hold g.mu.
+func (s *RunStats) update(last statsState, current *statsState) {
+	if current.wiped && !last.wiped {
+		s.Wipes++
+	}
+
+	switch {
+	case current.inBattle && !last.inBattle:
+		s.Battles++
+		current.battleStartExp = current.exp
+	case current.inBattle:
+		current.battleStartExp = last.battleStartExp
+	case last.inBattle:
+		if current.exp <= last.battleStartExp && !current.wiped {
+			s.BattlesFled++
+		}
+	}
+
+	for name, v := range current.position {
+		if last.position[name] != v {
+			s.Steps++
+			break
+		}
+	}
+}
+
+// Caller must hold g.mu.
+func (s *RunStats) countInput(button string) {
+	if s.Inputs == nil {
+		s.Inputs = map[string]int{}
+	}
+
+	s.Inputs[button]++
+}
+
+// Returns the stats on one line, for the overlay.
+func (s RunStats) Summary() string {
+	return fmt.Sprintf(
+		"Wipes: %d  Battles: %d (fled %d)  Steps: %d",
+		s.Wipes,
+		s.Battles,
+		s.BattlesFled,
+		s.Steps,
+	)
+}
+
+// Returns the inputs, most pressed first, like "a: 120, up: 80".
+func (s RunStats) InputSummary() string {
+	var buttons []string
+	for button := range s.Inputs {
+		buttons = append(buttons, button)
+	}
+
+	sort.Slice(buttons, func(i, j int) bool {
+		if s.Inputs[buttons[i]] != s.Inputs[buttons[j]] {
+			return s.Inputs[buttons[i]] > s.Inputs[buttons[j]]
+		}
+		return buttons[i] < buttons[j]
+	})
+
+	parts := make([]string, len(buttons))
+	for i, button := range buttons {
+		parts[i] = fmt.Sprintf("%s: %d", button, s.Inputs[button])
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func (g *Game) showStats() {
+	ticker := time.NewTicker(time.Second)
+
+	for range ticker.C {
+		g.mu.Lock()
+		summary := g.stats.Summary()
+		g.mu.Unlock()
+
+		if err := g.Obs.UpdateStats(summary); err != nil {
+			fmt.Fprintln(os.Stderr, "Error updating stats on overlay:", err)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package game
+
+import (
+	"time"
+)
+
+// Viewer statistics for a single run of the stream, from when it was started
+// to when it was last saved.
+type ViewerSession struct {
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Peak    int       `json:"peak"`
+	Average float64   `json:"average"`
+	Samples int       `json:"samples"`
+}
+
+// Records the number of people watching at the given time.
+func (s *ViewerSession) record(viewers int, at time.Time) {
+	if viewers > s.Peak {
+		s.Peak = viewers
+	}
+
+	s.Samples += 1
+	s.Average += (float64(viewers) - s.Average) / float64(s.Samples)
+	s.End = at
+}
@@ -153,7 +153,7 @@ func configBasicProfilesMainServiceJson() (*asset, error) {
 	return a, nil
 }
 
-var _configBasicScenesMainJson = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xec\x5c\x5b\x73\x9b\xba\x1a\x7d\xcf\xaf\x60\x78\x6e\x5c\xae\x36\xce\x5b\xd2\xcb\x9c\x97\xf6\x74\xea\x9e\xbe\xb4\x1d\x46\x06\xd9\xd1\x09\x48\x6c\x5d\x92\x78\x77\xf2\xdf\xf7\x20\x7c\x01\x21\x08\xce\x4e\x13\x3b\x51\x9e\x32\xd6\x5a\xba\x7d\x6b\xa1\x0f\x24\xf8\x7d\x62\x59\x96\x65\x9f\x8b\xdb\x73\x91\x22\xf2\x1e\x5e\xa3\x04\xba\xf6\x99\x55\x15\xc8\xc2\x14\x22\xcc\x21\xcd\x40\x02\xe3\x05\x82\x59\x1a\x13\x9a\x42\x6a\x9f\x59\xce\x1b\x3d\x2a\x27\x29\x54\x8a\x21\x06\xf3\x0c\xa6\xf6\x99\xc5\xa9\x80\xb5\x82\x45\x06\x96\x4c\x01\x5f\x12\x7e\x05\x57\xac\xd1\x0d\x59\x90\xa1\x39\x99\xb3\x51\x2e\x78\x59\xff\x8f\x5f\x6f\xb4\xc5\x85\x60\x97\xa7\x9c\x9c\x0e\x84\x71\x90\x5d\xf5\xc0\x04\xde\xd6\xb3\x2d\xbf\xab\x75\x16\x95\x83\xb2\x0b\x91\x31\x18\x23\x5c\x08\x1e\x27\xa0\xe0\x82\x42\xbb\x06\xca\xd1\x2d\xa4\xe5\x80\xbc\x30\xac\xff\x4c\x30\xe2\x84\x22\xbc\x8c\xf9\xaa\x50\xe7\xac\x6c\xb7\xac\x7c\x01\x32\x56\x9f\x32\x0c\xf2\x12\x6a\x7f\x42\xc9\xdb\x73\x71\x5b\x6f\x47\x19\xba\xca\xac\x17\x9f\xa6\x30\x03\x2b\xa5\x49\x65\x4e\xba\xf8\x65\xb1\x96\xcf\x20\xe7\x08\x2f\x35\xa1\x4b\xa5\xb4\xe2\x6a\xb6\x52\xb8\x00\x22\xe3\xb6\x76\x42\xd9\x0a\x27\x4a\xb5\xd7\x24\x13\x72\xcc\xee\xc8\x39\xa9\xe1\xed\xf7\x90\x5d\x71\x52\x18\xf1\x3e\x82\x78\x89\xe0\x4f\xa9\xde\x75\xe8\x2c\x19\xbb\xd7\xac\xe1\x44\x50\x0a\x31\x8f\x0b\x4a\x96\x14\xe4\x31\x4b\x20\x96\x33\x34\x93\xff\x28\xa8\xfe\x52\x4e\x01\x66\x88\x23\x82\x4b\xc8\x47\x90\x6e\x11\x39\x49\x45\x06\x9b\x83\xb2\xab\x90\x9f\x72\x94\x4b\x4b\x28\xc3\x05\x82\x93\x19\x07\x94\x7f\x85\x09\xa1\xe9\xb7\x35\x4a\x99\xd0\x26\x74\xc6\x29\x04\x79\x1f\x94\xee\x2a\xfb\x0f\x11\x54\xb1\x8f\x0a\xf9\x84\xb0\xe0\xf0\x1e\xd0\x0c\x26\x04\xa7\x25\xc8\x57\x51\x6c\xd7\x9f\xae\xd6\x6a\x90\xee\xd6\x6a\xa0\x7a\x6b\xbb\xe0\x37\x42\xba\xbd\x42\x03\x84\x37\x01\x28\x28\xbc\x46\xf0\x26\xce\x48\x72\xa5\x1a\xc3\xfe\x4b\xa0\xe4\xaa\x16\xbd\xb2\xf6\x1f\xdb\xca\x55\x19\x0a\x0a\xd6\x21\xf6\x1d\xb5\xa3\xbb\xcb\x4f\xeb\x9a\x20\x75\xeb\x2a\x3f\x6e\xba\xfa\x4e\xe8\xa5\xfc\x98\x6d\x7b\x1d\x6d\x4b\x9d\x2a\x53\xb9\xae\xc0\x66\xe0\x1a\xa6\xf1\x66\xf2\x0a\x4a\xfe\x0f\x13\x4e\x68\xef\x04\xf5\x70\x9c\x01\x63\x34\x74\x43\xef\xa6\x6b\xd5\xb9\x8f\x2a\x6b\x58\x7b\x88\xe5\x0c\xef\x45\xf3\x9a\x7a\x4a\x40\x56\xe6\x53\xcd\x24\xd4\x96\x8b\xfe\x36\x73\xed\x54\xd8\xe6\x7a\x5a\xa5\x06\x5d\x4d\x10\x41\x13\xd8\xbf\xc0\x0c\x49\x9a\x5b\xc8\x76\xe2\x6c\xf5\x25\xcf\x56\x47\x02\x6d\x29\x49\xf4\x9d\x6e\x25\xb1\x39\xbc\xe5\xf1\x82\x7b\x71\x35\x1c\x5b\x01\x6d\x33\x56\xb5\xe2\xde\x9c\xd5\xea\xc9\x5b\x1b\x13\x7c\x9e\x70\x74\x0d\xad\x22\x03\x2b\x48\xd9\x68\x34\x52\xdb\xef\xcf\x61\x5b\x10\x5d\x1e\x6a\xdd\x9f\xcb\xb6\x20\x9d\xf5\x74\xe6\xb4\x55\x14\x08\xe6\xda\x92\xaa\x14\x24\x72\xd0\xdf\xbf\xf9\x9e\xaf\x0c\x74\x07\xd2\x07\x72\xd7\x01\xf4\x77\x59\x89\x1f\x74\x95\xf3\x55\x26\x5b\xf9\x0a\x97\x22\x03\xd4\x6e\xc1\xee\xda\x4c\x7b\x41\x49\x1e\x2f\x90\x64\xb6\xd5\x25\x21\x19\xd9\xba\x49\x33\x7b\x12\x52\x69\xa9\xaa\xc5\xfe\xfd\x7b\x54\x45\xf7\x4b\x15\xdc\x2f\x80\x5f\xde\xdd\x35\xbb\xa3\x4a\xb2\x9d\xed\x5b\xda\x8c\xdf\xea\x4f\xad\x8c\xeb\xfa\x5d\xf7\x8d\x70\x90\x59\x05\x85\x8c\x41\x63\xba\x43\x36\x9d\xea\x28\x19\xb9\x2f\x55\xe0\x8c\xa1\xac\x43\x31\xd4\x27\xc2\xb8\x45\x61\x02\x31\x37\xb6\xaa\x81\x8e\xc5\x56\x65\xfc\xbe\xca\xf0\x19\x6f\xd5\x8b\x2a\x6f\xa1\x1c\x2c\xe1\xf3\x18\xeb\x23\x21\x1c\xd2\x23\x37\xd2\x4e\x67\xef\x08\x5e\xa0\x65\x25\xae\xb7\x80\x31\xc8\xd9\xdb\x85\x1c\xe2\xa8\xc0\x4b\x23\xb7\xe7\x96\xdb\x3b\x82\x39\x25\x59\xf6\xc2\x25\x97\x6c\x87\x69\x64\xb7\x95\x5d\x42\x32\x42\x9f\x47\x76\x17\x20\xb9\x5a\x52\x22\x70\x7a\xdc\xb2\x93\x53\x68\x9f\x59\x81\x37\x99\x4e\x1d\x67\x3c\x8d\x5e\x9b\xb4\xda\x73\xf2\xdf\x8b\xd9\x05\x60\x28\x19\xcd\x60\x06\x13\x3e\x5b\xef\x3a\xa9\xcf\xf5\x25\x76\xbd\xd7\x78\x89\x52\x58\x6d\x4f\xc5\x88\xc3\x7c\xd4\x7e\x48\xb2\x1f\xbf\x26\xaf\xfd\x88\xb5\xcb\xe1\x7e\xc4\xf5\xb2\xbd\x1f\xa9\x23\x89\xde\xaf\x92\xcf\x1f\x66\xd6\x87\x5c\x64\x80\x93\x7d\xdb\xff\x0c\x6f\xb9\x35\x17\x9c\x13\x5c\xb5\x6f\x21\xbc\x7f\x07\x5a\xf7\xd6\x0f\xa1\x8b\x82\xa3\x1c\xee\xcf\xfe\x4e\x38\xb4\xe6\x14\x82\xab\x94\xdc\x0c\xe8\x3c\xbb\x24\x37\xff\x46\x69\x2a\x7f\xb0\xd2\x54\xe2\x60\xa5\xa9\xc4\x41\x4a\x53\x49\x0f\x52\x9a\x5a\xc9\x1e\x4a\x6b\x51\x1f\xa4\x34\xb5\x96\x3d\x95\xa6\xa7\x0f\x55\x9a\xca\xd6\x2b\xad\xf7\x72\x5f\x2d\xb3\xac\xb6\xdb\xbe\x2d\xfa\xc3\xeb\xeb\x4c\xd7\xe6\x71\x2d\xad\xe5\xa4\x37\xb7\x1a\xea\x7f\xfa\x9b\x74\x49\x04\x19\x5a\x62\xfb\xcc\x0a\xf5\xf7\xd8\x12\x33\x2f\x1d\xab\x6f\xb8\x81\xbb\x2d\xbb\x3d\xea\xb8\x9b\xdf\xa2\x56\x15\xaa\x13\xa4\xb9\x67\x57\x7a\x12\x6f\x3a\xdd\xd3\xd4\x06\xaa\x97\x45\x03\x99\x50\x52\xc4\x73\xc2\x39\xc9\x87\x20\x33\xb8\xe0\x43\x70\x14\x2d\x2f\x07\x01\x39\x29\xee\x81\xdd\x9b\x08\x36\xd0\x05\x39\x88\x58\x51\xc2\xef\x6b\x43\x6e\xc3\xc1\x61\x9d\xf5\x47\xe1\x54\xfe\x39\xc1\xd8\xf7\xc2\x71\xd4\xf1\x54\xa8\xd1\xf7\x36\xe9\x41\x43\x91\xdd\x8c\x17\x28\xab\xd6\x12\x3b\x45\xac\xcc\x03\xfb\x82\x70\x8d\x18\x9a\x6f\x9f\x31\x69\x71\x1d\x2d\x1a\xb3\xbe\x0c\xb3\xaa\x0b\xe8\x63\x38\x76\x32\x0e\x06\x85\x6c\x3c\x0e\x0f\xc3\xb5\xee\xa0\xee\xba\x0f\xec\xac\xf1\x65\x17\xc0\xf8\xb2\x05\x1b\xb8\xbb\xd9\xe0\x3c\xbe\x31\x5d\x63\x4c\x3d\xc5\x18\xf3\xb5\x1b\xf3\xbe\xc3\x3e\x0d\xd2\x1e\xce\x1c\x0f\x0a\x5a\x58\x3a\xd8\x38\x53\x43\x31\xce\x7c\xed\xce\x1c\x74\x7e\xa1\xc1\xdc\xc3\x9e\x93\x41\x91\x0b\x22\xdf\xd8\x53\x4b\x31\xf6\x7c\xed\xf6\x6c\x3f\x6c\x7d\x4a\x67\xfa\x81\x3b\x72\x1d\xc7\x71\xc6\xae\xe3\x87\x6e\x38\xf6\x8c\x4d\x35\x14\x63\xd3\xd7\x6e\xd3\xae\x6d\x9d\xa7\x34\xab\x37\x3d\x9c\x2c\xd7\xf7\xbc\x30\x72\x7c\x7f\x12\x7a\x41\xe0\x0e\x79\x9c\x5b\x92\xe4\x9f\xe3\x86\x41\xe0\x46\xd3\xd0\xb8\x77\x8d\x33\xee\xad\xe3\xfe\x80\x7b\x3b\xcf\x7e\x35\xd0\x7b\x18\x36\x1c\x66\xd8\x03\xd9\x7e\x71\xca\x35\xde\x0f\x9d\x68\x32\x75\x7d\x6f\x12\x4c\xc6\x3d\xaa\xb5\x76\x5a\x73\x1d\x27\x70\x82\x28\x8c\xc2\x68\xea\x4e\x3c\x7f\x6c\x1c\xbb\xc6\x19\xc7\xd6\x71\x7f\x62\xbd\xad\x9f\xc0\x78\x0c\xcf\x1e\xd5\x7e\xa9\x49\x80\x37\x18\x63\xc8\x3a\xf2\x19\x0d\xa9\x3d\xad\xdf\x40\x0e\xb6\xa2\x17\x0d\xcb\x77\xc7\xd3\xf1\x61\xd8\xd1\x19\xb9\xae\xe7\xf8\x8e\x33\x09\x9c\x72\x21\x8d\xc2\x68\xd8\xfa\xe9\x3a\x91\xeb\x44\x4e\x14\xf9\x93\xc0\xf5\x27\xd3\x43\xb2\x6b\xeb\xd7\xfe\x13\x5e\x2f\xf0\x40\xaf\xfe\x10\xdb\x6d\x42\xf2\x82\x30\xc4\xd7\x5f\xaa\x7a\xe2\xf3\x6c\x3d\x0b\xdf\x91\x1d\x6b\x5b\x7f\x23\x29\xbe\x41\x38\x25\x37\xe5\xd8\x42\x2f\xf0\xa2\xc8\x99\xfc\xa4\x3f\xf1\x02\x61\x90\xc5\x0b\x80\x39\x60\xab\x11\x86\xac\xfc\xf1\xf3\x87\x99\x46\xd6\x36\xbb\x01\x45\x4c\x61\x3a\xcf\x84\x56\xd3\xaf\x56\xab\xcf\xfb\x66\xe4\xb0\x67\x22\xc7\xa6\x5a\xc1\x38\xc9\xe3\x1b\x94\xf2\x4b\xfd\xd2\xf8\xe2\xdf\x9e\xec\x8a\xed\x99\xce\x9b\xea\xeb\x96\x25\xf1\x42\xf2\xe4\xeb\x96\xe6\x65\x4b\xeb\x50\xec\x7a\xdf\x4e\xc3\x91\x19\xf5\xa5\xbb\xb0\x6e\xaa\x32\x74\x17\x9b\xc8\x19\x4b\x59\x87\x62\xa9\xfe\x63\xa2\xc6\x50\x6a\xf9\xc1\x18\x4a\x06\xee\x7f\x32\x6e\x8f\x6e\x27\xf9\xdf\xe6\x43\x52\xbb\xaf\x15\xc6\xba\xaf\x02\xda\xca\xd7\x0c\x7f\x9d\xdc\x9d\xfc\x13\x00\x00\xff\xff\xf6\x84\xd9\x31\x6f\x58\x00\x00")
+var _configBasicScenesMainJson = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xed\x9d\x5b\x73\x9b\x38\x14\x80\xdf\xf3\x2b\x3c\x3c\x27\x2e\x57\x5f\xfa\xd6\xf4\x32\xfb\xd2\xdd\x4c\x9d\xed\x4b\xdb\x61\x64\x90\x6d\x6d\xb0\xe4\x05\x91\xc4\x9b\xc9\x7f\x5f\x21\x12\x07\xcb\x02\xcb\x6e\xea\xe0\xf4\xb8\x7d\xc8\xa0\x73\x74\x3b\xe7\xe3\x1c\x10\x88\xbb\x93\x8e\xf8\x59\xef\xf2\xdb\x77\x79\x4c\xd8\x07\x7c\x4d\x22\xec\x58\x6f\x3b\x77\xb2\x40\x16\xc6\x98\x50\x8e\xd3\x04\x45\x38\x9c\x10\x9c\xc4\x21\x4b\x63\x9c\x0a\x21\xfb\x54\x2f\x35\x67\x31\x56\x8a\x31\x45\xe3\x04\xc7\xe2\x28\x4f\x73\x5c\x29\x98\x24\x68\x9a\x29\xc2\x33\xc6\xaf\xf0\x32\x5b\xeb\x86\x2c\x48\xc8\x98\x8d\xb3\xee\x3c\xe7\x45\xfd\xdf\x7e\x9c\x6a\x8b\x17\x79\x36\x3b\xe3\xec\xcc\x50\x8c\xa3\xe4\xaa\x41\x2c\xa7\xab\x7a\x56\xe5\xf7\x95\xce\x92\x62\x50\xd6\x22\x4f\x32\x1c\x12\xba\xc8\x79\x18\xa1\x05\xcf\x53\x6c\x55\x84\xe6\xe4\x16\xa7\xc5\x80\xdc\x20\xa8\x1e\x66\x94\x70\x96\x12\x3a\x0d\xf9\x72\xa1\xce\x59\xd1\x6e\x51\xf9\x04\x89\xba\x2b\xc7\x29\x9a\x17\xa2\xd6\x67\x12\xbd\x11\x96\xab\xb6\xa3\x0c\x5d\xd5\xac\x16\x9f\xc5\x38\x41\x4b\xa5\x49\x65\x4e\xea\xf4\x8b\x62\xad\x7e\x86\x39\x17\xa3\xd1\x98\x2e\x96\xae\x15\x96\xb3\x15\xe3\x09\xca\x13\x6e\x69\x27\x34\x5b\xd2\x48\xa9\xf6\x9a\x25\xb9\x1c\xb3\xd3\xb5\x4f\x2a\xf2\xd6\x07\x9c\x5d\x71\xb6\x00\xe7\x7d\x06\xe7\x65\x39\x3f\xa4\xf7\x3e\x98\xae\x23\x6d\xf7\x3b\xfb\x70\x94\xa7\x29\xa6\x3c\x5c\xa4\x6c\x9a\xa2\x79\x98\x45\x98\xca\x19\x1a\xc9\x3f\x14\xa9\xe6\x52\x9e\x22\x9a\x11\x4e\x18\x2d\x44\x3e\xa1\x78\x25\x21\xdc\x3a\x4f\xf0\xfa\xa0\xac\xd2\xe4\x67\x9c\xcc\x25\x12\xca\x70\x51\xce\xd9\x88\xa3\x94\x7f\xc1\x91\xa0\xe6\xf2\x41\x4a\x99\xd0\x75\xd1\x11\x4f\x31\x9a\x37\x89\xa6\x4f\x95\xfd\xc1\xf2\x54\xc1\x47\x15\xf9\x4c\xa8\xb0\xf3\x16\xa1\x91\xf8\x93\xc6\x85\x90\xa7\x4a\x65\x4f\xfd\xa9\x6b\xad\x22\x52\xdf\x5a\x45\xa8\xda\xda\x93\xf1\xd7\x4c\xba\x3a\x43\x23\x42\x1f\x0d\xb0\x48\x85\xf7\xe0\x9b\x30\x61\xd1\x95\x0a\x86\xf5\x6f\x4e\xa2\xab\x8a\xf5\x8a\xda\xbf\xad\x2a\x57\xdd\x30\x4f\xd1\x83\x89\x3d\x5b\xed\xe8\xd3\xe9\x67\xe3\x9c\x20\xfd\xd6\x51\x0e\x3e\x76\xf5\x7d\xae\x77\xe5\xe7\x6c\xdb\xad\x69\x5b\xfa\xa9\x32\x95\x0f\x15\x58\x19\xba\xc6\x71\xf8\x38\x79\x82\x91\x7f\x70\x24\x4e\x3a\x8d\x13\xd4\xa0\x63\x1b\x8c\x11\xd4\x41\xbd\x5e\x5d\xeb\x9d\xbb\x78\x65\x45\xd6\xb2\x76\xe8\x16\xe8\xbd\x4a\xbd\x75\x7f\x8a\x50\x52\xe4\x53\xeb\x49\xa8\x25\x83\xfe\x2a\x73\xad\xf5\xb0\xc7\xf3\x69\x99\x1a\xd4\x35\x21\xc2\x60\x84\x9b\x03\x8c\x49\xd2\x6c\x90\x38\x37\x26\xcf\x75\x09\xb4\x9a\x44\xdf\xeb\x22\x89\xc5\xf1\x2d\x0f\x27\xdc\x0d\xcb\xe1\x58\x8a\xd0\x2a\x63\x55\x2b\x6e\xcc\x59\x9b\xf2\xd6\xb5\x09\x7e\x17\x71\x72\x8d\x3b\x0b\x91\x3c\x8a\x66\xba\xdd\xae\xda\x7e\x73\x0e\x6b\x94\xc7\x1a\xe4\xb2\x46\xf9\x6c\x73\x4e\x5b\x5a\x81\x51\xae\x2d\x29\x4b\x85\x71\x8b\x41\x7f\xbd\xf4\x5c\x4f\x19\xe8\x36\x43\x3e\x75\x80\xfc\x57\x54\xe2\xf9\x75\xe5\x7c\x99\xc8\x56\xbe\xe0\x69\x9e\xa0\xd4\xda\x10\xbb\x3f\xd5\x74\x3c\x65\x73\xe1\xa1\x52\x73\xd3\xbb\xca\x0b\x21\xb6\xa2\x49\x33\x7b\x52\xa4\xf4\xa5\xb2\x16\xeb\xee\xae\x5b\x5a\xf7\xa2\x34\xee\x05\xe2\xb3\xfb\xfb\xf5\xee\xa8\x2e\xb9\x99\xed\xeb\x33\xfe\x2d\xa9\x15\x50\xd7\x4c\xdd\x25\x13\x3e\xde\x11\xf1\x39\xcb\x30\x40\xd7\x66\xe8\x54\xa2\xa4\xe5\x2e\x4a\xc3\x01\x50\xad\x01\xea\x33\xcb\x78\x47\x5c\x47\x63\xca\x01\xab\x23\xc4\xaa\xb0\xdf\x17\x69\x3e\x60\x6b\x93\x2d\x32\x47\x53\xfc\x32\x60\x7d\x62\x4c\x0c\xf9\xc8\x41\x7a\xf2\xb3\xf7\x8c\x4e\xc8\xb4\x74\xae\x37\x48\x38\x1a\xcf\xde\x4c\xe4\x10\xbb\x0b\x3a\x05\x77\x7b\x69\x77\x13\xf6\xe1\x29\x4b\x92\x57\xee\x72\xd1\x6a\x98\xe0\x76\x2b\xb7\x8b\x58\xc2\xd2\x97\x71\xbb\x73\x14\x5d\x4d\x53\x96\xd3\xf8\xb8\xdd\x4e\x4e\xa1\x28\xf2\xdd\xfe\x70\x68\xdb\xbd\xe1\xe0\x77\x73\xad\xcd\x39\xf9\xeb\x7c\x74\x8e\x32\x12\x75\x47\x38\xc1\x11\x1f\x3d\xac\x3a\xa9\xf7\xf5\xab\x6b\x8d\x33\x12\xe3\x72\x79\x2a\x24\x1c\xcf\xbb\x9b\x37\x49\x76\xd3\xaf\xb8\xd7\x6e\x8a\x95\xd3\xe1\x6e\x8a\x0f\x61\x7b\x37\xa5\x9a\x24\x7a\xb7\x4a\xfe\xfc\x38\xea\x7c\x9c\x8b\x64\x91\xb3\x5d\xdb\xff\x53\x24\x85\x9d\x71\xce\x39\xa3\x65\xfb\x1d\x42\x77\xef\xc0\xc6\xb5\xf5\x3e\xea\xf9\xa2\x58\x49\xdc\x5d\xfb\xab\x98\xf6\xce\x38\xc5\xe8\x2a\x66\x37\x06\x9d\xcf\x66\xec\xe6\x67\x3c\x4d\xd5\x37\xf6\x34\x55\xd1\xd8\xd3\x54\x45\x23\x4f\x53\x95\xf6\xf2\x34\xb5\x92\x1d\x3c\x6d\x43\x75\x2f\x4f\x53\x6b\xd9\xd1\xd3\xf4\xea\xa6\x9e\xa6\x6a\xeb\x3d\xad\xf1\x74\x5f\x86\xd9\xac\xb2\xda\x7e\xa8\xf8\x3a\xd2\xb5\x79\x5c\xa1\xb5\x98\xf4\xf5\xa5\x86\xea\x4f\x7f\x91\x5e\x3e\x50\x90\x90\x69\xb1\xc8\x1c\x9c\xd6\xcb\x8c\x0b\x62\xb3\xda\x8b\xfd\x95\xdc\x6d\xd1\xed\xae\x7d\xda\x2c\xb5\x2c\xa5\x6a\x85\xee\xb7\xf6\x24\x7c\xec\xb4\xbd\x5d\x54\xef\x16\xeb\x69\x49\xca\x16\xe1\x98\x09\xdc\xe6\x26\x92\x09\x9e\x70\x13\xb9\x94\x4c\x67\x46\x82\x9c\x2d\xb6\x88\x6d\x4d\x04\xd7\xdd\x89\xb5\xc2\x56\x29\xe3\xdb\xda\x90\xcb\x70\xd8\xac\xb3\x5e\x37\x18\xca\x9f\xed\xf7\x3c\x37\xe8\x0d\x7c\x83\xbe\x6f\x2a\xed\x35\x14\xd9\xcd\xe2\x86\x50\x19\x4b\xac\x98\x64\x45\x1e\xd8\x64\x84\x6b\x92\x91\xf1\xea\x1e\xd3\xc9\x0e\x2d\x02\xac\xaf\x03\xd6\x11\x47\x5c\x73\x9b\x77\x3f\x54\xfb\xbd\xbe\x91\xad\xfa\x76\x4b\x70\x75\x8c\xba\xeb\xec\xd9\x59\x00\x12\x80\xdc\x19\xc8\x0b\x94\xf2\xe5\x73\x01\xe9\xb8\xbe\x99\xb1\xdc\xa1\x0f\x44\x02\x91\x40\xa4\xee\x7e\xfa\x0c\x3f\x63\x8c\x74\x1c\xcf\xcc\x5a\xfe\xc0\x03\x24\x01\x49\x40\x52\x83\xe4\x25\xe1\x09\x3e\x38\x91\x3d\x17\xd2\x56\x20\x12\x88\xd4\x11\xf9\x95\xe0\x1b\xed\xc3\xaf\xbf\x98\xc9\xa0\x07\x89\x2b\x30\x09\x4c\x6a\xa3\xa4\xb2\x38\xf2\x3c\xb7\x78\x7c\xb3\x58\xd9\x0b\x80\x4b\xe0\x12\xb8\xac\xe5\xb2\xf6\x11\xdb\x5f\x0c\xa6\x03\x60\x02\x98\x00\xa6\x0e\xcc\x6d\x2f\x72\xed\x4b\x66\x0f\x52\x59\x20\x13\xc8\xfc\x09\x32\x8d\xde\x4d\xd9\x17\xcf\x3e\xdc\x8f\x05\x3c\x01\xcf\x9f\xb9\xfb\xb3\xf1\x20\xdd\x21\xc9\xf4\x7c\xa7\xeb\xd8\xe2\xd7\x73\x6c\x2f\x70\x82\x9e\x0b\x98\x02\xa6\x80\xe9\x06\xa6\x75\x8f\xec\x1e\x12\xd6\x36\x3d\x69\xe0\xb9\x6e\x30\xb0\x3d\xaf\x1f\xb8\xbe\xef\xf8\x66\xb8\x7a\xf2\x67\x3b\x81\x50\x19\x0c\x03\xa0\x17\xe8\x3d\x0c\xbd\xb5\xef\xf5\xed\x0b\x6c\x60\x06\x6c\x4b\x16\x3d\xed\x22\xc6\x7b\x81\x3d\xe8\x0f\x1d\xcf\xed\xfb\xa2\xff\x46\xbe\x26\xb4\x7c\xdb\x1f\x04\xe2\xdf\xd0\xe9\xbb\x5e\x0f\x88\x05\x62\x0f\x14\x6f\xab\x6f\xd7\xfc\x76\xcf\xc2\x43\x02\x0c\x40\xb6\x0c\x48\xed\x4e\x0c\xfb\xa1\xe8\x0e\xcc\xf2\xdd\xde\xb0\xd7\x9a\xf8\xe9\xb8\xb6\x67\xdb\x7d\xdf\x2e\x02\xa9\x88\x87\x66\xf1\xd3\xb1\x07\xe2\xbf\x3d\x18\x78\x7d\xdf\xf1\xfa\x43\xc0\x15\x70\x3d\xd0\x7a\x0c\xa5\x62\x54\x11\x9e\x63\xca\x9f\xeb\x3a\xd5\xb5\x8f\x2a\xeb\x3d\xca\x20\xba\x71\xf4\x07\xec\xce\x51\x4c\xef\x6d\xc4\xe6\xc2\x51\x09\x7f\xf8\x36\xc0\x81\xdf\x20\x6e\x48\x47\x8f\x6d\x8f\x8e\x72\x57\xfa\xf0\x86\xd0\x98\xdd\x14\x63\x0b\x5c\xdf\x1d\x0c\xec\xfe\xf7\xf4\x3b\x9d\x10\x8a\x92\x70\x82\x28\x47\xd9\xb2\x4b\x71\x56\x1c\x14\x83\xd7\xb8\xb5\x95\xdd\x20\x71\xea\xc2\xf1\x38\xc9\xb5\x3e\x0d\x7b\xd1\xbd\xc8\x66\x32\x66\x77\x2a\x8f\xcd\x6b\xf3\x4c\xc4\x5c\xe1\xb4\x31\x9f\xe9\x23\xe0\xab\xdf\xaf\xae\xce\xb6\x6f\x2d\x83\x0d\xee\x0a\xc5\x73\xa9\x27\x37\xb8\x83\xed\xed\x5a\x83\xeb\xb6\xf5\x3f\xd8\x35\xb2\x55\x14\x56\xa1\x2a\x4c\x77\xfe\x68\x39\x40\xaa\x35\x48\x35\x3f\xbc\x0d\x40\xb5\x16\x28\x69\xb8\xbf\xa5\xdd\x00\xa7\xf6\x44\xa8\xba\xf7\x93\x80\xa4\xf6\x86\xa6\xd2\x66\x40\x51\x7b\x82\x92\xfe\xbd\x5b\x60\xa8\x86\x21\xf7\xe5\xa3\x51\x61\x31\x20\xa8\x35\x04\xd5\x6d\x26\x01\x08\xd5\x20\xe4\xf4\x5e\x1a\xa1\xd2\x64\xc0\x50\x6b\x18\xaa\xd9\x22\x09\x10\x6a\x2d\x42\xd2\x62\x40\x50\x6b\x08\x6a\x5e\x58\x05\x90\x5a\x7b\x49\x54\x35\x1c\xf0\xd4\x1a\x9e\x6a\x76\xd1\x04\x90\x5a\x1b\x91\xa4\xc5\x9e\x9d\xa0\xb5\x4f\x6b\x3e\x7d\xbf\x39\xd4\x7d\x27\xd9\x52\xbe\xef\xfc\xe3\xe4\xfe\xe4\x7f\x20\x9d\x92\x0d\x81\x81\x00\x00")
 
 func configBasicScenesMainJsonBytes() ([]byte, error) {
 	return bindataRead(
@@ -168,7 +168,7 @@ func configBasicScenesMainJson() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "config/basic/scenes/Main.json", size: 22639, mode: os.FileMode(420), modTime: time.Unix(1493072732, 0)}
+	info := bindataFileInfo{name: "config/basic/scenes/Main.json", size: 33153, mode: os.FileMode(420), modTime: time.Unix(1792420225, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
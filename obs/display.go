@@ -22,16 +22,45 @@ func (o *Obs) drawDefaults() error {
 	if err := o.UpdateActivePlayers(0); err != nil {
 		return err
 	}
-	if err := o.updateMostRecentPresses(); err != nil {
+	if err := o.drawCounters(); err != nil {
+		return err
+	}
+	if err := o.UpdateTotalUptime(0); err != nil {
+		return err
+	}
+	if err := o.UpdateViewers(0); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(o.TitlePath, []byte(o.Title), os.ModePerm); err != nil {
 		return err
 	}
-	if err := o.updateTotalPresses(); err != nil {
+	if err := o.UpdateCheats(nil); err != nil {
+		return err
+	}
+	if err := o.UpdateParty(nil, 0); err != nil {
+		return err
+	}
+	if err := o.UpdateAnnouncement(""); err != nil {
+		return err
+	}
+	if err := o.UpdateStats(""); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+func (o *Obs) drawCounters() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if err := o.updateMostRecentPresses(); err != nil {
+		return err
+	}
+
+	return o.updateTotalPresses()
+}
+
 func (o *Obs) UpdateNextButtonPress(secondsRemaining int) error {
 	return ioutil.WriteFile(
 		o.NextButtonPressPath,
@@ -60,6 +89,33 @@ DOWN: %s RIGHT: %s B: %s
 	return ioutil.WriteFile(o.VoteBreakdownPath, []byte(str), os.ModePerm)
 }
 
+type ActionVotes struct {
+	Reaction string
+	Action   string
+	Votes    int
+}
+
+// Used instead of UpdateVoteBreakdown while the game is on a screen with its
+// own controls, since the controller on the overlay doesn't apply.
+func (o *Obs) UpdateScreenVoteBreakdown(screen string, breakdown []ActionVotes) error {
+	var lines []string
+
+	// Two to a line, like the controller's breakdown.
+	line := ""
+	for i, votes := range breakdown {
+		line += fmt.Sprintf("%6s=%-8s %s ", votes.Reaction, votes.Action, padCount(votes.Votes))
+
+		if i%2 == 1 || i == len(breakdown)-1 {
+			lines = append(lines, strings.TrimRight(line, " "))
+			line = ""
+		}
+	}
+
+	str := fmt.Sprintf("%s votes:\n\n%s\n", strings.Title(screen), strings.Join(lines, "\n"))
+
+	return ioutil.WriteFile(o.VoteBreakdownPath, []byte(str), os.ModePerm)
+}
+
 func padCount(num int) string {
 	const length = 5
 	const padder = " " // Character to pad with
@@ -68,6 +124,9 @@ func padCount(num int) string {
 }
 
 func (o *Obs) AddMostRecentPress(newPress string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
 	o.mostRecentPresses = append([]string{strings.ToUpper(newPress)}, o.mostRecentPresses...)
 
 	if len(o.mostRecentPresses) > 3 {
@@ -77,6 +136,7 @@ func (o *Obs) AddMostRecentPress(newPress string) error {
 	return o.updateMostRecentPresses()
 }
 
+// Caller must hold o.mu.
 func (o *Obs) updateMostRecentPresses() error {
 	return ioutil.WriteFile(
 		o.MostRecentPressesPath,
@@ -91,12 +151,81 @@ func (o *Obs) UpdateActivePlayers(count int) error {
 	return ioutil.WriteFile(o.ActivePlayersPath, []byte(str), os.ModePerm)
 }
 
+func (o *Obs) UpdateViewers(count int) error {
+	str := fmt.Sprintf("Viewers: %d", count)
+
+	return ioutil.WriteFile(o.ViewersPath, []byte(str), os.ModePerm)
+}
+
+// Takes a line for each cheat that's active or being voted for.
+func (o *Obs) UpdateCheats(cheats []string) error {
+	str := ""
+	if len(cheats) > 0 {
+		str = fmt.Sprintf("Cheats (comment !cheat NAME):\n%s", strings.Join(cheats, "\n"))
+	}
+
+	return ioutil.WriteFile(o.CheatsPath, []byte(str), os.ModePerm)
+}
+
+type PartyMember struct {
+	Name     string
+	Class    string
+	Level    int
+	HP       int
+	MaxHP    int
+	MP       int
+	Ailments []string
+}
+
+// Shows each party member on two lines, to fit beside the vote breakdown.
+func (o *Obs) UpdateParty(party []PartyMember, gold int) error {
+	if len(party) == 0 {
+		return ioutil.WriteFile(o.PartyPath, nil, os.ModePerm)
+	}
+
+	var lines []string
+
+	for _, member := range party {
+		lines = append(lines, strings.TrimSpace(fmt.Sprintf(
+			"%-4s %s L%d %s",
+			member.Name,
+			member.Class,
+			member.Level,
+			strings.Join(member.Ailments, " "),
+		)))
+
+		stats := fmt.Sprintf(" HP%d/%d", member.HP, member.MaxHP)
+		if member.MP > 0 {
+			stats += fmt.Sprintf(" MP%d", member.MP)
+		}
+
+		lines = append(lines, stats)
+	}
+
+	lines = append(lines, fmt.Sprintf("Gold: %d", gold))
+
+	return ioutil.WriteFile(o.PartyPath, []byte(strings.Join(lines, "\n")), os.ModePerm)
+}
+
+// Shows a message over the game. Empty to hide it.
+func (o *Obs) UpdateAnnouncement(message string) error {
+	return ioutil.WriteFile(o.AnnouncementPath, []byte(message), os.ModePerm)
+}
+
+func (o *Obs) UpdateStats(summary string) error {
+	return ioutil.WriteFile(o.StatsPath, []byte(summary), os.ModePerm)
+}
+
 func (o *Obs) IncrementButtonPresses() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
 	o.buttonPressCount += 1
 
 	return o.updateTotalPresses()
 }
 
+// Caller must hold o.mu.
 func (o *Obs) updateTotalPresses() error {
 	return ioutil.WriteFile(
 		o.TotalPressesPath,
@@ -105,12 +234,11 @@ func (o *Obs) updateTotalPresses() error {
 	)
 }
 
-func (o *Obs) UpdateTotalUptime(startTime, currentTime time.Time) error {
-	delta := currentTime.Sub(startTime)
-	days := int(delta.Hours()) / int(time.Hour*24)
-	hours := int(delta.Hours()) - int(days*24)
-	minutes := int(delta.Minutes()) - int(hours*60)
-	seconds := int(delta.Seconds()) - int(minutes*60)
+func (o *Obs) UpdateTotalUptime(uptime time.Duration) error {
+	days := int(uptime / (24 * time.Hour))
+	hours := int(uptime/time.Hour) % 24
+	minutes := int(uptime/time.Minute) % 60
+	seconds := int(uptime/time.Second) % 60
 
 	str := fmt.Sprintf(
 		"Total uptime: %sD, %sH, %sM, %sS",
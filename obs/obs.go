@@ -2,19 +2,32 @@ package obs
 
 import (
 	"os/exec"
+	"sync"
 )
 
 type Obs struct {
 	StreamUrl string
 	StreamKey string
 
+	// Title of the game being played.
+	Title string
+
 	NextButtonPressPath   string
 	VoteBreakdownPath     string
 	MostRecentPressesPath string
 	ActivePlayersPath     string
 	TotalPressesPath      string
 	TotalUptimePath       string
+	ViewersPath           string
+	TitlePath             string
+	CheatsPath            string
+	PartyPath             string
+	AnnouncementPath      string
+	StatsPath             string
 
+	// Guards the counters below, which are saved while buttons are pressed.
+	// A pointer since Obs is passed around by value.
+	mu                *sync.Mutex
 	buttonPressCount  int
 	mostRecentPresses []string
 }
@@ -23,9 +36,29 @@ func New(streamUrl, streamKey string) Obs {
 	return Obs{
 		StreamUrl: streamUrl,
 		StreamKey: streamKey,
+		mu:        &sync.Mutex{},
 	}
 }
 
+// Picks the press counters back up from a previous run. Must be called before
+// Start.
+func (o *Obs) RestoreCounters(buttonPressCount int, mostRecentPresses []string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.buttonPressCount = buttonPressCount
+	o.mostRecentPresses = mostRecentPresses
+}
+
+// Returns the total number of button presses and the most recent presses,
+// newest first.
+func (o *Obs) Counters() (buttonPressCount int, mostRecentPresses []string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.buttonPressCount, append([]string{}, o.mostRecentPresses...)
+}
+
 func (o *Obs) Start() error {
 	if err := o.setup(); err != nil {
 		return err
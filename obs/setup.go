@@ -66,6 +66,36 @@ func (o *Obs) setupTmpFiles() (err error) {
 		return err
 	}
 
+	o.ViewersPath, err = createTmp("viewers")
+	if err != nil {
+		return err
+	}
+
+	o.TitlePath, err = createTmp("title")
+	if err != nil {
+		return err
+	}
+
+	o.CheatsPath, err = createTmp("cheats")
+	if err != nil {
+		return err
+	}
+
+	o.PartyPath, err = createTmp("party")
+	if err != nil {
+		return err
+	}
+
+	o.AnnouncementPath, err = createTmp("announcement")
+	if err != nil {
+		return err
+	}
+
+	o.StatsPath, err = createTmp("stats")
+	if err != nil {
+		return err
+	}
+
 	// Set default values
 	if err := o.drawDefaults(); err != nil {
 		return err
@@ -199,6 +229,36 @@ func (o *Obs) cleanupTmpFiles() error {
 	}
 	o.TotalUptimePath = ""
 
+	if err := os.Remove(o.ViewersPath); err != nil {
+		return err
+	}
+	o.ViewersPath = ""
+
+	if err := os.Remove(o.TitlePath); err != nil {
+		return err
+	}
+	o.TitlePath = ""
+
+	if err := os.Remove(o.CheatsPath); err != nil {
+		return err
+	}
+	o.CheatsPath = ""
+
+	if err := os.Remove(o.PartyPath); err != nil {
+		return err
+	}
+	o.PartyPath = ""
+
+	if err := os.Remove(o.AnnouncementPath); err != nil {
+		return err
+	}
+	o.AnnouncementPath = ""
+
+	if err := os.Remove(o.StatsPath); err != nil {
+		return err
+	}
+	o.StatsPath = ""
+
 	return nil
 }
 
@@ -0,0 +1,90 @@
+package rom
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"os"
+	"strings"
+)
+
+// Run `go generate` to regenerate dbData.go any time a file in ./data/
+// changes.
+
+//go:generate go-bindata -o dbData.go -pkg rom data/...
+
+const bundledDatabasePath = "data/nes.dat"
+
+// Titles of known ROMs, keyed by Hash or, for entries that only have one, the
+// headerless CRC32 (see CRC).
+type Database map[string]string
+
+// The parts of a No-Intro (Logiqx XML) DAT file we care about. No-Intro's NES
+// DATs checksum ROMs without their iNES header, the same as Hash.
+type datFile struct {
+	Games []struct {
+		Name string `xml:"name,attr"`
+		Roms []struct {
+			SHA1  string `xml:"sha1,attr"`
+			CRC32 string `xml:"crc,attr"`
+		} `xml:"rom"`
+	} `xml:"game"`
+}
+
+// Loads the database bundled with the stream, which covers the games it has
+// memory maps for.
+func BundledDatabase() (Database, error) {
+	data, err := Asset(bundledDatabasePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseDatabase(bytes.NewReader(data))
+}
+
+// Loads a database from the No-Intro DAT file at the given path.
+func LoadDatabase(path string) (Database, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ParseDatabase(f)
+}
+
+// Parses a No-Intro DAT file.
+func ParseDatabase(r io.Reader) (Database, error) {
+	var dat datFile
+	if err := xml.NewDecoder(r).Decode(&dat); err != nil {
+		return nil, err
+	}
+
+	db := Database{}
+
+	for _, game := range dat.Games {
+		for _, rom := range game.Roms {
+			if rom.SHA1 != "" {
+				db[strings.ToLower(rom.SHA1)] = game.Name
+			}
+			if rom.CRC32 != "" {
+				db[strings.ToLower(rom.CRC32)] = game.Name
+			}
+		}
+	}
+
+	return db, nil
+}
+
+// Returns the title of the ROM with the given hash or CRC32.
+func (db Database) Title(hash string) (string, bool) {
+	title, ok := db[hash]
+	return title, ok
+}
+
+// Adds the entries in other to db, replacing any for the same ROM.
+func (db Database) Merge(other Database) {
+	for key, title := range other {
+		db[key] = title
+	}
+}
@@ -0,0 +1,237 @@
+// Code generated by go-bindata.
+// sources:
+// data/nes.dat
+// DO NOT EDIT!
+
+package rom
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+func bindataRead(data []byte, name string) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewBuffer(data))
+	if err != nil {
+		return nil, fmt.Errorf("Read %q: %v", name, err)
+	}
+
+	var buf bytes.Buffer
+	_, err = io.Copy(&buf, gz)
+	clErr := gz.Close()
+
+	if err != nil {
+		return nil, fmt.Errorf("Read %q: %v", name, err)
+	}
+	if clErr != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+type asset struct {
+	bytes []byte
+	info  os.FileInfo
+}
+
+type bindataFileInfo struct {
+	name    string
+	size    int64
+	mode    os.FileMode
+	modTime time.Time
+}
+
+func (fi bindataFileInfo) Name() string {
+	return fi.name
+}
+func (fi bindataFileInfo) Size() int64 {
+	return fi.size
+}
+func (fi bindataFileInfo) Mode() os.FileMode {
+	return fi.mode
+}
+func (fi bindataFileInfo) ModTime() time.Time {
+	return fi.modTime
+}
+func (fi bindataFileInfo) IsDir() bool {
+	return false
+}
+func (fi bindataFileInfo) Sys() interface{} {
+	return nil
+}
+
+var _dataNesDat = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x75\x50\x4b\x4f\x02\x31\x10\x3e\xc3\xaf\x18\x7b\x82\xc3\x6e\x05\x89\x07\x53\x96\x00\x0b\x6a\xc2\x2b\x02\x07\x8f\x65\x3b\xec\x36\xd9\xb6\xd8\x56\x01\x7f\xbd\x65\x23\x68\x7c\x9c\x3a\x6d\xbf\xe7\xb0\xde\x41\x95\xf0\x86\xd6\x49\xa3\xbb\xa4\x15\x5f\x93\x5e\x52\x67\x57\xe9\x7c\xb8\x7a\x5e\x8c\x40\x70\xcf\xb7\xb2\x44\x58\xac\x07\x93\xc7\x21\x90\x88\xd2\x89\xc9\xe5\xcb\x81\xd2\x74\x95\xc2\xd3\x7c\x0a\x53\xae\x79\x8e\x0a\xb5\x87\xf4\x13\x4e\xe9\x68\x46\x80\x14\xde\xef\xee\x28\xdd\xef\xf7\x71\x59\x91\xe2\xcc\x28\x1a\x40\x8e\x9e\x85\x63\xe1\x05\x09\x8e\xe7\x7b\x52\xaf\xb1\x02\xb9\x40\x1b\xa6\x1a\xd3\x5c\x61\x32\x93\xda\xa3\x16\x06\x22\xb8\x8c\xa3\x70\x5a\xcf\xa5\xae\x7c\x97\x47\xe7\x51\x41\xe3\xa1\x62\x96\xe8\x5c\x93\xd1\x8a\x7b\x12\x11\xe8\x32\x2b\x77\x3e\x54\x4c\xee\xc3\xa3\x03\x5f\x20\x38\x6f\x91\x2b\x28\xb8\x83\xcd\xab\x16\x25\x0a\x50\xe8\xf9\x29\x08\x6c\x8d\x8d\x61\xc1\x9d\x83\x28\xb2\x46\x45\x62\x03\xde\x00\x17\x02\x8c\x05\x13\xd6\x65\xa5\x40\x08\xce\x56\xa2\x8b\x19\xfd\xee\x10\x0a\xd0\x4b\x03\x96\x07\x3f\x38\x25\xe9\x92\xb1\xd4\xbc\x84\x31\xd7\x9e\xbb\x23\x34\xd6\xcb\x7e\x93\xfc\xca\xf7\x07\xe8\xa7\x7c\x8d\x85\x48\xff\x6b\xc6\x1a\x1d\x01\x27\xdf\xc3\x77\xfb\xb6\xdd\xea\x74\x08\x64\x36\xeb\x92\xe1\x68\x90\xb6\xfb\x37\x2d\x42\xab\x8c\x79\xb5\x1e\x46\xbf\x36\xff\x01\x95\xb6\xe9\x62\x0d\x02\x00\x00")
+
+func dataNesDatBytes() ([]byte, error) {
+	return bindataRead(
+		_dataNesDat,
+		"data/nes.dat",
+	)
+}
+
+func dataNesDat() (*asset, error) {
+	bytes, err := dataNesDatBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "data/nes.dat", size: 525, mode: os.FileMode(420), modTime: time.Unix(1792421126, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+// Asset loads and returns the asset for the given name.
+// It returns an error if the asset could not be found or
+// could not be loaded.
+func Asset(name string) ([]byte, error) {
+	cannonicalName := strings.Replace(name, "\\", "/", -1)
+	if f, ok := _bindata[cannonicalName]; ok {
+		a, err := f()
+		if err != nil {
+			return nil, fmt.Errorf("Asset %s can't read by error: %v", name, err)
+		}
+		return a.bytes, nil
+	}
+	return nil, fmt.Errorf("Asset %s not found", name)
+}
+
+// MustAsset is like Asset but panics when Asset would return an error.
+// It simplifies safe initialization of global variables.
+func MustAsset(name string) []byte {
+	a, err := Asset(name)
+	if err != nil {
+		panic("asset: Asset(" + name + "): " + err.Error())
+	}
+
+	return a
+}
+
+// AssetInfo loads and returns the asset info for the given name.
+// It returns an error if the asset could not be found or
+// could not be loaded.
+func AssetInfo(name string) (os.FileInfo, error) {
+	cannonicalName := strings.Replace(name, "\\", "/", -1)
+	if f, ok := _bindata[cannonicalName]; ok {
+		a, err := f()
+		if err != nil {
+			return nil, fmt.Errorf("AssetInfo %s can't read by error: %v", name, err)
+		}
+		return a.info, nil
+	}
+	return nil, fmt.Errorf("AssetInfo %s not found", name)
+}
+
+// AssetNames returns the names of the assets.
+func AssetNames() []string {
+	names := make([]string, 0, len(_bindata))
+	for name := range _bindata {
+		names = append(names, name)
+	}
+	return names
+}
+
+// _bindata is a table, holding each asset generator, mapped to its name.
+var _bindata = map[string]func() (*asset, error){
+	"data/nes.dat": dataNesDat,
+}
+
+// AssetDir returns the file names below a certain
+// directory embedded in the file by go-bindata.
+// For example if you run go-bindata on data/... and data contains the
+// following hierarchy:
+//     data/
+//       foo.txt
+//       img/
+//         a.png
+//         b.png
+// then AssetDir("data") would return []string{"foo.txt", "img"}
+// AssetDir("data/img") would return []string{"a.png", "b.png"}
+// AssetDir("foo.txt") and AssetDir("notexist") would return an error
+// AssetDir("") will return []string{"data"}.
+func AssetDir(name string) ([]string, error) {
+	node := _bintree
+	if len(name) != 0 {
+		cannonicalName := strings.Replace(name, "\\", "/", -1)
+		pathList := strings.Split(cannonicalName, "/")
+		for _, p := range pathList {
+			node = node.Children[p]
+			if node == nil {
+				return nil, fmt.Errorf("Asset %s not found", name)
+			}
+		}
+	}
+	if node.Func != nil {
+		return nil, fmt.Errorf("Asset %s not found", name)
+	}
+	rv := make([]string, 0, len(node.Children))
+	for childName := range node.Children {
+		rv = append(rv, childName)
+	}
+	return rv, nil
+}
+
+type bintree struct {
+	Func     func() (*asset, error)
+	Children map[string]*bintree
+}
+var _bintree = &bintree{nil, map[string]*bintree{
+	"data": &bintree{nil, map[string]*bintree{
+		"nes.dat": &bintree{dataNesDat, map[string]*bintree{}},
+	}},
+}}
+
+// RestoreAsset restores an asset under the given directory
+func RestoreAsset(dir, name string) error {
+	data, err := Asset(name)
+	if err != nil {
+		return err
+	}
+	info, err := AssetInfo(name)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(_filePath(dir, filepath.Dir(name)), os.FileMode(0755))
+	if err != nil {
+		return err
+	}
+	err = ioutil.WriteFile(_filePath(dir, name), data, info.Mode())
+	if err != nil {
+		return err
+	}
+	err = os.Chtimes(_filePath(dir, name), info.ModTime(), info.ModTime())
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// RestoreAssets restores an asset under the given directory recursively
+func RestoreAssets(dir, name string) error {
+	children, err := AssetDir(name)
+	// File
+	if err != nil {
+		return RestoreAsset(dir, name)
+	}
+	// Dir
+	for _, child := range children {
+		err = RestoreAssets(dir, filepath.Join(name, child))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func _filePath(dir, name string) string {
+	cannonicalName := strings.Replace(name, "\\", "/", -1)
+	return filepath.Join(append([]string{dir}, strings.Split(cannonicalName, "/")...)...)
+}
+
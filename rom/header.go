@@ -0,0 +1,141 @@
+package rom
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+)
+
+const (
+	FormatINES  = "iNES"
+	FormatNES20 = "NES 2.0"
+
+	prgUnit     = 0x4000
+	chrUnit     = 0x2000
+	trainerSize = 512
+)
+
+// Mappers the emulator implements.
+var supportedMappers = map[int]string{
+	0: "NROM",
+	1: "MMC1",
+	2: "UxROM",
+	3: "CNROM",
+	4: "MMC3",
+	7: "AxROM",
+}
+
+var ErrNotINES = errors.New("not an iNES ROM")
+
+// The parts of an iNES or NES 2.0 header we care about. See
+// https://www.nesdev.org/wiki/INES and https://www.nesdev.org/wiki/NES_2.0
+type Header struct {
+	Format    string
+	Mapper    int
+	Submapper int
+
+	// In bytes.
+	PRGSize int
+	CHRSize int
+
+	Battery        bool
+	Trainer        bool
+	VerticalMirror bool
+	FourScreenVRAM bool
+}
+
+// Parses the header at the start of a ROM and checks that the ROM is as large
+// as the header says it is.
+func ParseHeader(data []byte) (Header, error) {
+	var h Header
+
+	if len(data) < inesHeaderSize || !bytes.Equal(data[:len(inesMagic)], inesMagic) {
+		return h, ErrNotINES
+	}
+
+	flags6, flags7 := data[6], data[7]
+
+	h.Format = FormatINES
+	h.Mapper = int(flags6 >> 4)
+	h.VerticalMirror = flags6&0x01 != 0
+	h.Battery = flags6&0x02 != 0
+	h.Trainer = flags6&0x04 != 0
+	h.FourScreenVRAM = flags6&0x08 != 0
+
+	switch flags7 & 0x0c {
+	case 0x08:
+		h.Format = FormatNES20
+		h.Mapper |= int(flags7&0xf0) | int(data[8]&0x0f)<<8
+		h.Submapper = int(data[8] >> 4)
+		h.PRGSize = nes20Size(data[4], data[9]&0x0f, prgUnit)
+		h.CHRSize = nes20Size(data[5], data[9]>>4, chrUnit)
+	case 0x00:
+		// Old dumping tools wrote their name over the end of the header, which
+		// leaves garbage in the upper nibble of the mapper. Only trust it if the
+		// padding is clean.
+		if bytes.Equal(data[12:16], []byte{0, 0, 0, 0}) {
+			h.Mapper |= int(flags7 & 0xf0)
+		}
+		fallthrough
+	default:
+		h.PRGSize = int(data[4]) * prgUnit
+		h.CHRSize = int(data[5]) * chrUnit
+	}
+
+	if h.PRGSize == 0 {
+		return h, errors.New("ROM has no PRG ROM")
+	}
+
+	size := inesHeaderSize + h.PRGSize + h.CHRSize
+	if h.Trainer {
+		size += trainerSize
+	}
+
+	if len(data) < size {
+		return h, fmt.Errorf("ROM is truncated: header says it's %d bytes, but it's only %d", size, len(data))
+	}
+
+	return h, nil
+}
+
+// NES 2.0 sizes are either a count of units, with msb as the upper bits, or an
+// exponent and multiplier when msb is 0xf.
+func nes20Size(lsb, msb byte, unit int) int {
+	if msb != 0x0f {
+		return (int(msb)<<8 | int(lsb)) * unit
+	}
+
+	exponent := uint(lsb >> 2)
+	multiplier := int(lsb&0x03)*2 + 1
+
+	// Far larger than any real ROM, so it's caught as truncated.
+	if exponent > 30 {
+		return math.MaxInt32
+	}
+
+	return (1 << exponent) * multiplier
+}
+
+// Returns the name of the header's mapper, if the emulator supports it.
+func (h Header) SupportedMapper() (string, bool) {
+	name, ok := supportedMappers[h.Mapper]
+	return name, ok
+}
+
+func (h Header) String() string {
+	mapper, ok := h.SupportedMapper()
+	if !ok {
+		mapper = "unsupported"
+	}
+
+	return fmt.Sprintf(
+		"%s, mapper %d (%s), %d KiB PRG, %d KiB CHR, battery: %t",
+		h.Format,
+		h.Mapper,
+		mapper,
+		h.PRGSize/1024,
+		h.CHRSize/1024,
+		h.Battery,
+	)
+}
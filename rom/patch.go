@@ -0,0 +1,277 @@
+package rom
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+var (
+	ipsMagic = []byte("PATCH")
+	ipsEOF   = []byte("EOF")
+	bpsMagic = []byte("BPS1")
+
+	errPatchTruncated = errors.New("patch is truncated")
+)
+
+// Far larger than any NES ROM.
+const maxPatchedSize = 64 << 20
+
+// Applies an IPS or BPS patch to a ROM, returning the patched ROM. BPS patches
+// are checked against the checksums of the ROM they were made from and the ROM
+// they should produce. IPS patches don't have checksums.
+func ApplyPatch(rom, patch []byte) ([]byte, error) {
+	switch {
+	case bytes.HasPrefix(patch, ipsMagic):
+		return applyIPS(rom, patch)
+	case bytes.HasPrefix(patch, bpsMagic):
+		return applyBPS(rom, patch)
+	default:
+		return nil, errors.New("not an IPS or BPS patch")
+	}
+}
+
+// Applies the patches at the given paths, in order, to the ROM at romPath and
+// writes the result to dir, named after its hash. Returns the path of the
+// patched ROM, or romPath if there are no patches. Since the patched ROM's
+// contents differ from the original's, so does its save directory.
+func PatchFile(romPath string, patchPaths []string, dir string) (string, error) {
+	if len(patchPaths) == 0 {
+		return romPath, nil
+	}
+
+	rom, err := ReadPatched(romPath, patchPaths)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, Hash(rom)+".nes")
+
+	return path, ioutil.WriteFile(path, rom, 0644)
+}
+
+// Reads the ROM at romPath and applies the patches at the given paths to it, in
+// order, without writing anything.
+func ReadPatched(romPath string, patchPaths []string) ([]byte, error) {
+	rom, err := ioutil.ReadFile(romPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, patchPath := range patchPaths {
+		patch, err := ioutil.ReadFile(patchPath)
+		if err != nil {
+			return nil, err
+		}
+
+		rom, err = ApplyPatch(rom, patch)
+		if err != nil {
+			return nil, fmt.Errorf("error applying %s: %v", patchPath, err)
+		}
+	}
+
+	return rom, nil
+}
+
+// See http://fileformats.archiveteam.org/wiki/IPS_(binary_patch_format)
+func applyIPS(rom, patch []byte) ([]byte, error) {
+	out := append([]byte{}, rom...)
+	r := bytes.NewReader(patch[len(ipsMagic):])
+
+	for {
+		var offsetBytes [3]byte
+		if _, err := io.ReadFull(r, offsetBytes[:]); err != nil {
+			return nil, errPatchTruncated
+		}
+
+		if bytes.Equal(offsetBytes[:], ipsEOF) {
+			break
+		}
+
+		offset := int(offsetBytes[0])<<16 | int(offsetBytes[1])<<8 | int(offsetBytes[2])
+
+		var size uint16
+		if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+			return nil, errPatchTruncated
+		}
+
+		var data []byte
+
+		if size == 0 {
+			// Run length encoded.
+			var run struct {
+				Size  uint16
+				Value byte
+			}
+			if err := binary.Read(r, binary.BigEndian, &run); err != nil {
+				return nil, errPatchTruncated
+			}
+
+			data = bytes.Repeat([]byte{run.Value}, int(run.Size))
+		} else {
+			data = make([]byte, size)
+			if _, err := io.ReadFull(r, data); err != nil {
+				return nil, errPatchTruncated
+			}
+		}
+
+		if end := offset + len(data); end > len(out) {
+			out = append(out, make([]byte, end-len(out))...)
+		}
+
+		copy(out[offset:], data)
+	}
+
+	// Some patches truncate the ROM after the end marker.
+	var truncate [3]byte
+	if _, err := io.ReadFull(r, truncate[:]); err == nil {
+		size := int(truncate[0])<<16 | int(truncate[1])<<8 | int(truncate[2])
+		if size < len(out) {
+			out = out[:size]
+		}
+	}
+
+	return out, nil
+}
+
+// See https://www.romhacking.net/documents/746/
+func applyBPS(rom, patch []byte) ([]byte, error) {
+	const footerSize = 12
+
+	if len(patch) < len(bpsMagic)+footerSize {
+		return nil, errPatchTruncated
+	}
+
+	footer := patch[len(patch)-footerSize:]
+	sourceChecksum := binary.LittleEndian.Uint32(footer[0:4])
+	targetChecksum := binary.LittleEndian.Uint32(footer[4:8])
+	patchChecksum := binary.LittleEndian.Uint32(footer[8:12])
+
+	if crc32.ChecksumIEEE(patch[:len(patch)-4]) != patchChecksum {
+		return nil, errors.New("patch is corrupt")
+	}
+
+	if crc32.ChecksumIEEE(rom) != sourceChecksum {
+		return nil, errors.New("patch was made for a different ROM")
+	}
+
+	p := bpsReader{data: patch[:len(patch)-footerSize], pos: len(bpsMagic)}
+
+	sourceSize := p.number()
+	targetSize := p.number()
+	metadataSize := p.number()
+	p.pos += metadataSize
+
+	if p.err != nil || sourceSize != len(rom) || targetSize > maxPatchedSize || p.pos > len(p.data) {
+		return nil, errors.New("patch header is invalid")
+	}
+
+	out := make([]byte, 0, targetSize)
+	var sourceOffset, targetOffset int
+
+	for p.pos < len(p.data) && p.err == nil {
+		action := p.number()
+		length := action>>2 + 1
+
+		switch action & 3 {
+		case 0: // Source read
+			start := len(out)
+			if start+length > len(rom) {
+				return nil, errors.New("patch reads past the end of the ROM")
+			}
+			out = append(out, rom[start:start+length]...)
+		case 1: // Target read
+			if p.pos+length > len(p.data) {
+				return nil, errPatchTruncated
+			}
+			out = append(out, p.data[p.pos:p.pos+length]...)
+			p.pos += length
+		case 2: // Source copy
+			sourceOffset += p.signedNumber()
+			if sourceOffset < 0 || sourceOffset+length > len(rom) {
+				return nil, errors.New("patch copies from outside of the ROM")
+			}
+			out = append(out, rom[sourceOffset:sourceOffset+length]...)
+			sourceOffset += length
+		case 3: // Target copy, which can overlap what it's writing.
+			targetOffset += p.signedNumber()
+			if targetOffset < 0 || targetOffset >= len(out) {
+				return nil, errors.New("patch copies from outside of the output")
+			}
+			for i := 0; i < length; i++ {
+				out = append(out, out[targetOffset])
+				targetOffset++
+			}
+		}
+
+		if len(out) > targetSize {
+			return nil, errors.New("patch writes past the end of the output")
+		}
+	}
+
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	if len(out) != targetSize || crc32.ChecksumIEEE(out) != targetChecksum {
+		return nil, errors.New("patched ROM doesn't match the patch's checksum")
+	}
+
+	return out, nil
+}
+
+type bpsReader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+// Reads a variable length number.
+func (r *bpsReader) number() int {
+	var n, shift uint64 = 0, 1
+
+	for {
+		if r.pos >= len(r.data) || shift > 1<<56 {
+			r.err = errPatchTruncated
+			return 0
+		}
+
+		b := r.data[r.pos]
+		r.pos++
+
+		n += uint64(b&0x7f) * shift
+		if b&0x80 != 0 {
+			break
+		}
+
+		shift <<= 7
+		n += shift
+	}
+
+	if n > 1<<31 {
+		r.err = errors.New("patch has an out of range number")
+		return 0
+	}
+
+	return int(n)
+}
+
+// Reads a relative offset, whose lowest bit is its sign.
+func (r *bpsReader) signedNumber() int {
+	n := r.number()
+	if n&1 != 0 {
+		return -(n >> 1)
+	}
+
+	return n >> 1
+}
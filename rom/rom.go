@@ -0,0 +1,90 @@
+package rom
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"fmt"
+	"hash/crc32"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+const inesHeaderSize = 16
+
+var inesMagic = []byte("NES\x1a")
+
+// Returns a hash identifying the ROM at the given path by its contents. See
+// Hash.
+func HashFile(path string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return Hash(data), nil
+}
+
+// Returns the SHA-1 of the ROM's contents, leaving out the iNES header so the
+// same ROM dumped with a different (or cleaned up) header hashes the same.
+func Hash(data []byte) string {
+	return fmt.Sprintf("%x", sha1.Sum(withoutHeader(data)))
+}
+
+// Returns the CRC32 of the ROM's contents without the iNES header, which some
+// DAT entries only have.
+func CRC(data []byte) string {
+	return fmt.Sprintf("%08x", crc32.ChecksumIEEE(withoutHeader(data)))
+}
+
+func withoutHeader(data []byte) []byte {
+	if len(data) >= inesHeaderSize && bytes.Equal(data[:len(inesMagic)], inesMagic) {
+		return data[inesHeaderSize:]
+	}
+
+	return data
+}
+
+// What we know about a ROM.
+type Info struct {
+	Header
+
+	Hash  string
+	Title string
+
+	// Whether Title came from the database rather than the file name.
+	Known bool
+}
+
+// Reads and validates the ROM at the given path, making sure the emulator can
+// run it, and looks it up in db, which may be nil. ROMs that aren't in db are
+// titled after their file name.
+func Load(path string, db Database) (Info, error) {
+	var info Info
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return info, err
+	}
+
+	info.Header, err = ParseHeader(data)
+	if err != nil {
+		return info, err
+	}
+
+	if _, ok := info.SupportedMapper(); !ok {
+		return info, fmt.Errorf("mapper %d isn't supported", info.Mapper)
+	}
+
+	info.Hash = Hash(data)
+	info.Title, info.Known = db.Title(info.Hash)
+	if !info.Known {
+		info.Title, info.Known = db.Title(CRC(data))
+	}
+
+	if !info.Known {
+		info.Title = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	return info, nil
+}
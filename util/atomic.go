@@ -0,0 +1,246 @@
+package util
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+const (
+	ChecksumExtension = ".sha256"
+	PreviousExtension = ".prev"
+	TmpExtension      = ".tmp"
+)
+
+// Safely replaces the file at path. write is given a temporary path to create
+// the new file at, which is then synced to disk, checksummed, and renamed over
+// path. The file that was at path is kept as path + PreviousExtension so
+// there's always a good copy to fall back to if we crash midway through.
+//
+// A checksum is always moved before its file, so a crash between the two
+// leaves the latest file without a checksum (and assumed valid) rather than
+// next to the checksum of another generation.
+func WriteFileSafely(path string, write func(tmpPath string) error) error {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+
+	tmpPath := path + TmpExtension
+
+	if err := write(tmpPath); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	checksum, err := syncAndChecksum(tmpPath)
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	// Keep the current generation around as the previous one.
+	if exists, err := FileExists(path); err != nil {
+		return err
+	} else if exists {
+		if err := os.Rename(path+ChecksumExtension, path+PreviousExtension+ChecksumExtension); os.IsNotExist(err) {
+			// Don't leave the checksum of an older generation next to this one.
+			if err := os.Remove(path + PreviousExtension + ChecksumExtension); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		} else if err != nil {
+			return err
+		}
+
+		if err := os.Rename(path, path+PreviousExtension); err != nil {
+			return err
+		}
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+
+	if err := writeChecksum(path+ChecksumExtension, checksum); err != nil {
+		return err
+	}
+
+	return syncDir(filepath.Dir(path))
+}
+
+// Safely replaces the file at path with data. See WriteFileSafely.
+func WriteDataSafely(path string, data []byte) error {
+	return WriteFileSafely(path, func(tmpPath string) error {
+		return ioutil.WriteFile(tmpPath, data, 0644)
+	})
+}
+
+// Returns path if the file there matches its checksum, otherwise the path of
+// the previous generation if that one does. Files saved without a checksum are
+// assumed to be valid. The returned error satisfies os.IsNotExist if neither
+// file exists.
+func LatestValidFile(path string) (string, error) {
+	valid, err := ValidGenerations(path)
+	if err != nil {
+		return "", err
+	}
+
+	return valid[0], nil
+}
+
+// Returns the paths of the generations of the file at path that match their
+// checksums, latest first. See LatestValidFile.
+func ValidGenerations(path string) ([]string, error) {
+	var valid []string
+	foundAny := false
+
+	for _, candidate := range []string{path, path + PreviousExtension} {
+		exists, err := FileExists(candidate)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			continue
+		}
+
+		foundAny = true
+
+		ok, err := ValidChecksum(candidate)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			valid = append(valid, candidate)
+			continue
+		}
+
+		fmt.Fprintln(os.Stderr, "Checksum mismatch, ignoring corrupt file:", candidate)
+	}
+
+	if !foundAny {
+		return nil, &os.PathError{Op: "open", Path: path, Err: os.ErrNotExist}
+	}
+
+	if len(valid) == 0 {
+		return nil, fmt.Errorf("no valid copy of %s", path)
+	}
+
+	return valid, nil
+}
+
+// Returns the path of the generation of the file at path whose contents have
+// the given checksum, and whether or not there is one. Used to find the copy
+// of a file that was saved along with another.
+func GenerationWithChecksum(path, checksum string) (string, bool, error) {
+	for _, candidate := range []string{path, path + PreviousExtension} {
+		actual, err := FileChecksum(candidate)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return "", false, err
+		}
+
+		if actual == checksum {
+			return candidate, true, nil
+		}
+	}
+
+	return "", false, nil
+}
+
+// Returns whether the file at path matches the checksum saved alongside it.
+// Files without a saved checksum are assumed to be valid.
+func ValidChecksum(path string) (bool, error) {
+	expected, err := ioutil.ReadFile(path + ChecksumExtension)
+	if os.IsNotExist(err) {
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	actual, err := FileChecksum(path)
+	if err != nil {
+		return false, err
+	}
+
+	return bytes.Equal(bytes.TrimSpace(expected), []byte(actual)), nil
+}
+
+// Returns the SHA-256 of the file at path, as saved alongside it.
+func FileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// Flushes the file at path to disk and returns its checksum.
+func syncAndChecksum(path string) (string, error) {
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if err := f.Sync(); err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+func writeChecksum(path, checksum string) error {
+	tmpPath := path + TmpExtension
+
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(f, checksum+"\n"); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, path)
+}
+
+// Makes renames in the directory durable.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	// Not every platform supports syncing directories, and there's nothing more
+	// we can do if it isn't.
+	d.Sync()
+
+	return nil
+}
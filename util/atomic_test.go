@@ -0,0 +1,124 @@
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeGenerations(t *testing.T, path string, generations ...string) {
+	for _, data := range generations {
+		if err := WriteDataSafely(path, []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+func TestWriteFileSafelyKeepsPreviousGeneration(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "save.dat")
+	writeGenerations(t, path, "one", "two", "three")
+
+	if data := readFile(t, path); data != "three" {
+		t.Errorf("expected the latest generation, got %q", data)
+	}
+
+	if data := readFile(t, path+PreviousExtension); data != "two" {
+		t.Errorf("expected the previous generation, got %q", data)
+	}
+
+	for _, candidate := range []string{path, path + PreviousExtension} {
+		if valid, err := ValidChecksum(candidate); err != nil || !valid {
+			t.Errorf("expected %s to match its checksum, got %t, %v", candidate, valid, err)
+		}
+	}
+}
+
+func TestLatestValidFileFallsBack(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "save.dat")
+	writeGenerations(t, path, "one", "two")
+
+	if err := ioutil.WriteFile(path, []byte("corrupt"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	valid, err := LatestValidFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if valid != path+PreviousExtension {
+		t.Errorf("expected to fall back to %s, got %s", path+PreviousExtension, valid)
+	}
+}
+
+func TestLatestValidFileNotExist(t *testing.T) {
+	if _, err := LatestValidFile(filepath.Join(t.TempDir(), "save.dat")); !os.IsNotExist(err) {
+		t.Errorf("expected a not exist error, got %v", err)
+	}
+}
+
+// A crash after the checksum has been moved to the previous generation, but
+// before the file has, must still leave a valid copy to load.
+func TestCrashBetweenRenames(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "save.dat")
+	writeGenerations(t, path, "one", "two")
+
+	if err := os.Rename(path+ChecksumExtension, path+PreviousExtension+ChecksumExtension); err != nil {
+		t.Fatal(err)
+	}
+
+	valid, err := LatestValidFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if data := readFile(t, valid); data != "two" {
+		t.Errorf("expected the latest generation to load, got %q from %s", data, valid)
+	}
+
+	// Finishing the interrupted write must leave both generations consistent.
+	writeGenerations(t, path, "three")
+
+	for _, candidate := range []string{path, path + PreviousExtension} {
+		if valid, err := ValidChecksum(candidate); err != nil || !valid {
+			t.Errorf("expected %s to match its checksum, got %t, %v", candidate, valid, err)
+		}
+	}
+}
+
+func TestGenerationWithChecksum(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "save.dat")
+	writeGenerations(t, path, "one")
+
+	checksum, err := FileChecksum(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeGenerations(t, path, "two")
+
+	found, ok, err := GenerationWithChecksum(path, checksum)
+	if err != nil || !ok {
+		t.Fatalf("expected to find the generation, got %t, %v", ok, err)
+	}
+
+	if found != path+PreviousExtension {
+		t.Errorf("expected %s, got %s", path+PreviousExtension, found)
+	}
+
+	writeGenerations(t, path, "three")
+
+	if _, ok, err := GenerationWithChecksum(path, checksum); err != nil || ok {
+		t.Errorf("expected no generation once it's been replaced twice, got %t, %v", ok, err)
+	}
+}
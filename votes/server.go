@@ -0,0 +1,200 @@
+package votes
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Votes waiting for the game to take them. Once this many are queued, new ones
+// are turned away instead of holding up their requests.
+const voteQueueSize = 100
+
+var (
+	errMissingUserId = errors.New("user_id is required")
+	errUnknownButton = errors.New("unknown button")
+	errTooManyVotes  = errors.New("too many votes waiting, try again later")
+)
+
+// Accepts votes over HTTP and WebSockets and sends them down Votes. Every
+// request must be authenticated with the shared secret, either through an
+// "Authorization: Bearer <secret>" header or a "secret" query parameter (for
+// browsers, which can't set headers on WebSocket connections).
+//
+//	POST /votes      {"user_id": "1234", "button": "up"}
+//	GET  /votes/ws   one JSON vote per message
+//
+// Votes are queued on Votes. If the queue is full they're rejected, with a 503
+// over HTTP.
+type Server struct {
+	Addr   string
+	Secret string
+
+	Votes chan Vote
+
+	upgrader websocket.Upgrader
+}
+
+// The JSON representation of a vote sent by clients.
+type voteRequest struct {
+	UserId string `json:"user_id"`
+	Button string `json:"button"`
+}
+
+type voteResponse struct {
+	Ok    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+func NewServer(addr, secret string) *Server {
+	return &Server{
+		Addr:   addr,
+		Secret: secret,
+		Votes:  make(chan Vote, voteQueueSize),
+
+		upgrader: websocket.Upgrader{
+			// Clients are authenticated by the shared secret, so votes can come from
+			// pages hosted anywhere.
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	}
+}
+
+func (s *Server) ListenAndServe() error {
+	if s.Secret == "" {
+		return errors.New("a shared secret is required to accept votes")
+	}
+
+	srv := http.Server{Addr: s.Addr, Handler: s.handler()}
+
+	return srv.ListenAndServe()
+}
+
+func (s *Server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/votes", s.authenticated(s.handleVote))
+	mux.HandleFunc("/votes/ws", s.authenticated(s.handleWebSocket))
+
+	return mux
+}
+
+func (s *Server) authenticated(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		secret := r.URL.Query().Get("secret")
+		if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+			secret = strings.TrimPrefix(auth, "Bearer ")
+		}
+
+		if subtle.ConstantTimeCompare([]byte(secret), []byte(s.Secret)) != 1 {
+			writeResponse(w, http.StatusUnauthorized, errors.New("invalid secret"))
+			return
+		}
+
+		handler(w, r)
+	}
+}
+
+func (s *Server) handleVote(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeResponse(w, http.StatusMethodNotAllowed, errors.New("votes must be POSTed"))
+		return
+	}
+
+	var req voteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeResponse(w, http.StatusBadRequest, fmt.Errorf("invalid vote: %v", err))
+		return
+	}
+
+	vote, err := req.vote()
+	if err != nil {
+		writeResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := s.queue(vote); err != nil {
+		writeResponse(w, http.StatusServiceUnavailable, err)
+		return
+	}
+
+	writeResponse(w, http.StatusOK, nil)
+}
+
+func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade has already replied to the client.
+		return
+	}
+	defer conn.Close()
+
+	for {
+		var req voteRequest
+		if err := conn.ReadJSON(&req); err != nil {
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				fmt.Fprintln(os.Stderr, "Error reading vote from WebSocket:", err)
+			}
+
+			return
+		}
+
+		res := voteResponse{Ok: true}
+
+		vote, err := req.vote()
+		if err == nil {
+			err = s.queue(vote)
+		}
+		if err != nil {
+			res = voteResponse{Error: err.Error()}
+		}
+
+		if err := conn.WriteJSON(res); err != nil {
+			return
+		}
+	}
+}
+
+// Queues the vote without waiting for the game to take it.
+func (s *Server) queue(vote Vote) error {
+	select {
+	case s.Votes <- vote:
+		return nil
+	default:
+		return errTooManyVotes
+	}
+}
+
+func (req voteRequest) vote() (Vote, error) {
+	if req.UserId == "" {
+		return Vote{}, errMissingUserId
+	}
+
+	button, ok := ButtonForName(req.Button)
+	if !ok {
+		return Vote{}, fmt.Errorf("%v: %q", errUnknownButton, req.Button)
+	}
+
+	return Vote{
+		UserId: req.UserId,
+		Button: button,
+		Cast:   time.Now(),
+	}, nil
+}
+
+func writeResponse(w http.ResponseWriter, status int, err error) {
+	res := voteResponse{Ok: err == nil}
+	if err != nil {
+		res.Error = err.Error()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(res)
+}
@@ -0,0 +1,142 @@
+package votes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/paked/nes/nes"
+)
+
+const testSecret = "hunter2"
+
+func postVote(t *testing.T, s *Server, target, body string, header http.Header) (int, voteResponse) {
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	rec := httptest.NewRecorder()
+	s.handler().ServeHTTP(rec, req)
+
+	var res voteResponse
+	if err := json.NewDecoder(rec.Body).Decode(&res); err != nil {
+		t.Fatalf("invalid response %q: %v", rec.Body.String(), err)
+	}
+
+	return rec.Code, res
+}
+
+func bearer(secret string) http.Header {
+	return http.Header{"Authorization": []string{"Bearer " + secret}}
+}
+
+func TestVoteAuthentication(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		header http.Header
+		status int
+	}{
+		{"bearer", "/votes", bearer(testSecret), http.StatusOK},
+		{"query", "/votes?secret=" + testSecret, nil, http.StatusOK},
+		{"bearer wins over query", "/votes?secret=" + testSecret, bearer("wrong"), http.StatusUnauthorized},
+		{"wrong bearer", "/votes", bearer("wrong"), http.StatusUnauthorized},
+		{"wrong query", "/votes?secret=wrong", nil, http.StatusUnauthorized},
+		{"not bearer", "/votes", http.Header{"Authorization": []string{"Basic " + testSecret}}, http.StatusUnauthorized},
+		{"missing", "/votes", nil, http.StatusUnauthorized},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewServer("", testSecret)
+
+			status, res := postVote(t, s, test.target, `{"user_id": "1234", "button": "up"}`, test.header)
+			if status != test.status {
+				t.Errorf("expected status %d, got %d (%+v)", test.status, status, res)
+			}
+
+			if queued := len(s.Votes); (status == http.StatusOK) != (queued == 1) {
+				t.Errorf("expected a vote to be queued only when accepted, got %d", queued)
+			}
+		})
+	}
+}
+
+func TestVoteQueued(t *testing.T) {
+	s := NewServer("", testSecret)
+
+	status, res := postVote(t, s, "/votes", `{"user_id": "1234", "button": "Start"}`, bearer(testSecret))
+	if status != http.StatusOK || !res.Ok {
+		t.Fatalf("expected the vote to be accepted, got %d (%+v)", status, res)
+	}
+
+	vote := <-s.Votes
+	if vote.UserId != "1234" || vote.Button != nes.ButtonStart {
+		t.Errorf("expected user 1234 to vote for start, got %+v", vote)
+	}
+}
+
+func TestInvalidVotes(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"bad JSON", `{"user_id": "1234", "button": `},
+		{"not an object", `["up"]`},
+		{"missing user", `{"button": "up"}`},
+		{"unknown button", `{"user_id": "1234", "button": "turbo"}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewServer("", testSecret)
+
+			status, res := postVote(t, s, "/votes", test.body, bearer(testSecret))
+			if status != http.StatusBadRequest || res.Ok || res.Error == "" {
+				t.Errorf("expected a bad request with an error, got %d (%+v)", status, res)
+			}
+
+			if len(s.Votes) != 0 {
+				t.Error("expected the vote not to be queued")
+			}
+		})
+	}
+}
+
+func TestVoteMethodNotAllowed(t *testing.T) {
+	s := NewServer("", testSecret)
+
+	req := httptest.NewRequest(http.MethodGet, "/votes", nil)
+	req.Header.Set("Authorization", "Bearer "+testSecret)
+
+	rec := httptest.NewRecorder()
+	s.handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status %d, got %d", http.StatusMethodNotAllowed, rec.Code)
+	}
+}
+
+func TestVoteQueueFull(t *testing.T) {
+	s := NewServer("", testSecret)
+
+	for i := 0; i < voteQueueSize; i++ {
+		if status, res := postVote(t, s, "/votes", `{"user_id": "1234", "button": "a"}`, bearer(testSecret)); status != http.StatusOK {
+			t.Fatalf("expected vote %d to be accepted, got %d (%+v)", i+1, status, res)
+		}
+	}
+
+	status, res := postVote(t, s, "/votes", `{"user_id": "1234", "button": "a"}`, bearer(testSecret))
+	if status != http.StatusServiceUnavailable || res.Ok {
+		t.Errorf("expected status %d once the queue is full, got %d (%+v)", http.StatusServiceUnavailable, status, res)
+	}
+}
+
+func TestListenRequiresSecret(t *testing.T) {
+	if err := NewServer("127.0.0.1:0", "").ListenAndServe(); err == nil {
+		t.Error("expected an error starting without a secret")
+	}
+}
@@ -0,0 +1,35 @@
+package votes
+
+import (
+	"strings"
+	"time"
+
+	"github.com/paked/nes/nes"
+)
+
+// A button vote submitted by an external system (a custom web controller, a
+// chat bot, a test harness...) rather than through a Facebook reaction.
+type Vote struct {
+	UserId string
+	Button int
+	Cast   time.Time
+}
+
+var nameToButton = map[string]int{
+	"up":     nes.ButtonUp,
+	"down":   nes.ButtonDown,
+	"right":  nes.ButtonRight,
+	"left":   nes.ButtonLeft,
+	"a":      nes.ButtonA,
+	"b":      nes.ButtonB,
+	"start":  nes.ButtonStart,
+	"select": nes.ButtonSelect,
+}
+
+// Returns the NES button with the given name (case insensitive) and whether or
+// not it exists.
+func ButtonForName(name string) (int, bool) {
+	button, ok := nameToButton[strings.ToLower(name)]
+
+	return button, ok
+}